@@ -1,3 +1,3 @@
 module github.com/go-lean/fun
 
-go 1.21
+go 1.22
@@ -43,7 +43,16 @@ func (c *Chain) Merge(other *Chain) *Chain {
 }
 
 func (c *Chain) Clone() *Chain {
-	return New(c.steps...)
+
+	steps := make([]Step, len(c.steps))
+	copy(steps, c.steps)
+
+	return New(steps...)
+}
+
+// Len returns the number of steps in c.
+func (c *Chain) Len() int {
+	return len(c.steps)
 }
 
 func (c *Chain) Build(lastHandler Handler) Handler {
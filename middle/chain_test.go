@@ -0,0 +1,45 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package middle_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/resp"
+)
+
+func TestChain_Clone_AdditionsDoNotLeak(t *testing.T) {
+	step := func(name string) middle.Step {
+		return func(r *http.Request, next middle.Handler) resp.Result {
+			response := next(r)
+			response.Payload = fmt.Sprintf("%s%v", name, response.Payload)
+
+			return response
+		}
+	}
+
+	parent := middle.New(step("first")).Add(step("second")).Add(step("third"))
+
+	left := parent.Clone().Add(step("left"))
+	right := parent.Clone().Add(step("right"))
+
+	handler := func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, "handler", "text/plain")
+	}
+
+	r := httptest.NewRequest("", "/", nil)
+
+	ass.Equal(t, "firstsecondthirdlefthandler", left.Build(handler)(r).Payload.(string))
+	ass.Equal(t, "firstsecondthirdrighthandler", right.Build(handler)(r).Payload.(string))
+	ass.Equal(t, "firstsecondthirdhandler", parent.Build(handler)(r).Payload.(string))
+}
@@ -0,0 +1,22 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package middle
+
+import (
+	"net/http"
+
+	"github.com/go-lean/fun/resp"
+)
+
+// HTTP adapts handler to an http.HandlerFunc that writes the returned resp.Result with resp.Write.
+func HTTP(handler Handler) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = resp.Write(w, r, handler(r))
+	}
+}
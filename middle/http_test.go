@@ -0,0 +1,41 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package middle_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/resp"
+)
+
+func TestHTTP_WritesChainResult(t *testing.T) {
+	step := middle.Step(func(r *http.Request, next middle.Handler) resp.Result {
+		response := next(r)
+		response.Payload = fmt.Sprintf("step%v", response.Payload)
+
+		return response
+	})
+
+	handler := middle.New(step).Build(func(r *http.Request) resp.Result {
+		return resp.New(http.StatusTeapot, "handler", "text/plain")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	middle.HTTP(handler).ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
+	ass.Equal(t, "text/plain", w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, "stephandler", w.Body.String(), "wrong payload")
+}
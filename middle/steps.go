@@ -0,0 +1,45 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package middle
+
+import (
+	"net/http"
+
+	"github.com/go-lean/fun/resp"
+)
+
+// Negotiate is a Step that picks the representation of untyped results from the Accept header,
+// answering 406 when no registered type is acceptable.
+func Negotiate(r *http.Request, next Handler) resp.Result {
+	return resp.Negotiate(r, next(r))
+}
+
+// Problems is a Step that turns results carrying an error into problem details. Errors wrapping a
+// resp.Problem keep it, anything else becomes a problem with the result code, or 500 when the code
+// does not signal an error. The request path is used as the instance unless one is set.
+func Problems(r *http.Request, next Handler) resp.Result {
+
+	res := next(r)
+
+	err, ok := res.Payload.(error)
+	if !ok {
+		return res
+	}
+
+	code := res.Code
+	if code < http.StatusBadRequest {
+		code = http.StatusInternalServerError
+	}
+
+	problem := resp.ProblemFrom(code, err)
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+
+	return problem.Result(resp.WithHeaders(res.Header))
+}
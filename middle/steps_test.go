@@ -0,0 +1,87 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package middle_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/resp"
+)
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	called := false
+	step := middle.Step(func(r *http.Request, next middle.Handler) resp.Result {
+		response := next(r)
+		called = response.Code == http.StatusNotAcceptable
+
+		return response
+	})
+
+	handler := middle.New(step, middle.Negotiate).Build(func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, struct{}{}, "")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "image/png")
+
+	response := handler(r)
+
+	ass.Equal(t, http.StatusNotAcceptable, response.Code, "wrong status code")
+	ass.True(t, called, "outer step did not see the negotiated result")
+}
+
+func TestProblems_Error(t *testing.T) {
+
+	tc := []struct {
+		name   string
+		result resp.Result
+		status int
+		detail string
+	}{
+		{"client error", resp.New(http.StatusBadRequest, errors.New("baba"), ""), http.StatusBadRequest, "baba"},
+		{"success code with error", resp.New(http.StatusOK, errors.New("baba"), ""), http.StatusInternalServerError, ""},
+		{"wrapped problem", resp.New(http.StatusInternalServerError,
+			fmt.Errorf("wrapped: %w", resp.NewProblem(http.StatusConflict, "baba")), ""), http.StatusConflict, "baba"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			handler := middle.New(middle.Problems).Build(func(r *http.Request) resp.Result {
+				return c.result
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/baba", nil)
+
+			response := handler(r)
+			problem := response.Payload.(*resp.Problem)
+
+			ass.Equal(t, c.status, response.Code, "wrong status code")
+			ass.Equal(t, resp.TypeProblemJSON, response.Type, "wrong content type")
+			ass.Equal(t, c.detail, problem.Detail, "wrong detail")
+			ass.Equal(t, "/baba", problem.Instance, "wrong instance")
+		})
+	}
+}
+
+func TestProblems_NoError_Unchanged(t *testing.T) {
+	handler := middle.New(middle.Problems).Build(func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, "baba", "text/plain")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	response := handler(r)
+
+	ass.Equal(t, "baba", response.Payload.(string), "wrong payload")
+}
@@ -0,0 +1,105 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+type (
+	// ConflictPolicy decides what happens when a route can never be reached because a registered
+	// route has the same method and the same pattern up to parameter names.
+	ConflictPolicy int
+
+	ConflictError struct {
+		Method   string
+		Existing string
+		Pattern  string
+	}
+)
+
+const (
+	// PanicOnConflict panics on registration. It is the default.
+	PanicOnConflict ConflictPolicy = iota
+	// ErrorOnConflict skips the route and records the error, which Router.Err returns.
+	ErrorOnConflict
+	// WarnOnConflict skips the route and passes the error to Router.OnConflict.
+	WarnOnConflict
+)
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("mux: route %s %s conflicts with registered route %s %s", e.Method, e.Pattern, e.Method, e.Existing)
+}
+
+// Err returns the conflicts recorded under ErrorOnConflict, joined, or nil.
+func (r *Router) Err() error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return errors.Join(r.errs...)
+}
+
+// insert returns tree with route added and lists the route in t, reporting a conflict according
+// to r.Conflicts when the route would be shadowed by a registered one. The first registered route
+// always stays in place, and a skipped route is marked so it cannot be named.
+func (r *Router) insert(t *table, tree *node, route *Route) *node {
+
+	next := tree
+	for _, segments := range variants(route.segments) {
+		var existing *Route
+		if r.CaseInsensitive {
+			existing = next.shadowing(route, segments)
+		}
+
+		if existing == nil {
+			next, existing = next.insertAt(route, segments)
+		}
+
+		if existing != nil {
+			r.conflict(&ConflictError{
+				Method:   route.method,
+				Existing: existing.pattern,
+				Pattern:  route.pattern,
+			})
+
+			route.skipped = true
+			return tree
+		}
+	}
+
+	t.routes = append(t.routes, route)
+	return next
+}
+
+// conflict reports err according to r.Conflicts. It is called with r.mu held, so warnings are only
+// queued for update to pass on.
+func (r *Router) conflict(err *ConflictError) {
+
+	switch r.Conflicts {
+	case ErrorOnConflict:
+		r.errs = append(r.errs, err)
+	case WarnOnConflict:
+		r.warnings = append(r.warnings, err)
+	default:
+		panic(err.Error())
+	}
+}
+
+// warn passes err to r.OnConflict, or logs it when there is none.
+func (r *Router) warn(err *ConflictError) {
+
+	if r.OnConflict == nil {
+		log.Print(err)
+		return
+	}
+
+	r.OnConflict(err)
+}
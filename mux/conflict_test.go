@@ -0,0 +1,160 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_Conflicts_Panic(t *testing.T) {
+
+	tc := []struct {
+		name     string
+		existing string
+		pattern  string
+	}{
+		{"exact duplicate", "/users/:id", "/users/:id"},
+		{"renamed parameter", "/users/:id", "/users/:name"},
+		{"same constraint", "/users/:id<int>/posts", "/users/:user<int>/posts"},
+		{"renamed catch-all", "/static/*filepath", "/static/*rest"},
+		{"leading slash", "/users", "users"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Register(http.MethodGet, c.existing, dudHandler)
+
+			defer func() {
+				message, _ := recover().(string)
+
+				ass.True(t, strings.Contains(message, c.pattern), "pattern not named", message)
+				ass.True(t, strings.Contains(message, c.existing), "existing pattern not named", message)
+			}()
+
+			router.Register(http.MethodGet, c.pattern, dudHandler)
+		})
+	}
+}
+
+func TestRouter_Conflicts_NotConflicting(t *testing.T) {
+
+	tc := []struct {
+		name     string
+		existing string
+		pattern  string
+	}{
+		{"different constraints", "/users/:id<int>", "/users/:name"},
+		{"static and parameter", "/users/me", "/users/:id"},
+		{"parameter and catch-all", "/files/:name", "/files/*path"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Conflicts = mux.ErrorOnConflict
+
+			router.Register(http.MethodGet, c.existing, dudHandler)
+			router.Register(http.MethodGet, c.pattern, dudHandler)
+			router.Register(http.MethodPost, c.existing, dudHandler)
+
+			ass.Equal(t, nil, router.Err(), "unexpected conflict")
+		})
+	}
+}
+
+func TestRouter_Conflicts_Error(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.ErrorOnConflict
+
+	router.Register(http.MethodGet, "/users/:id", targetHandler)
+	router.Register(http.MethodGet, "/users/:name", dudHandler)
+	router.Mount("/static", pathHandler)
+	router.Mount("/static/", pathHandler)
+
+	var conflict *mux.ConflictError
+	err := router.Err()
+
+	ass.True(t, errors.As(err, &conflict), "missing conflict error")
+	ass.Equal(t, http.MethodGet, conflict.Method, "wrong method")
+	ass.Equal(t, "/users/:id", conflict.Existing, "wrong existing pattern")
+	ass.Equal(t, "/users/:name", conflict.Pattern, "wrong pattern")
+	ass.Equal(t, 2, len(err.(interface{ Unwrap() []error }).Unwrap()), "wrong conflict count")
+	ass.Equal(t, 2, len(router.Routes()), "conflicting route was registered")
+
+	w := serve(router, http.MethodGet, "/users/baba")
+	ass.Equal(t, "baba", w.Body.String(), "first route was replaced")
+}
+
+func TestRouter_Conflicts_Warn(t *testing.T) {
+
+	var conflicts []*mux.ConflictError
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.WarnOnConflict
+	router.OnConflict = func(err *mux.ConflictError) {
+		conflicts = append(conflicts, err)
+	}
+
+	router.Register(http.MethodGet, "/users/:id", targetHandler)
+	router.Register(http.MethodGet, "/users/:name", dudHandler)
+
+	ass.Equal(t, 1, len(conflicts), "wrong conflict count")
+	ass.Equal(t, nil, router.Err(), "warning recorded as error")
+	ass.Equal(t, "mux: route GET /users/:name conflicts with registered route GET /users/:id", conflicts[0].Error())
+}
+
+func TestRouter_Conflicts_WarnCallbackUsesRouter(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.WarnOnConflict
+
+	var listed int
+	router.OnConflict = func(err *mux.ConflictError) {
+		listed = len(router.Routes())
+		ass.Equal(t, nil, router.Err(), "warning recorded as error")
+
+		router.Register(http.MethodGet, "/members/:name", dudHandler).Name("member")
+	}
+
+	router.Register(http.MethodGet, "/users/:id", targetHandler)
+	router.Register(http.MethodGet, "/users/:name", dudHandler)
+
+	ass.Equal(t, 1, listed, "wrong route count in callback")
+
+	url, err := router.URL("member", "name", "baba")
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, "/members/baba", url, "route registered in callback missing")
+}
+
+func TestRouter_Conflicts_SkippedRouteIsNotNamed(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.ErrorOnConflict
+
+	router.Register(http.MethodGet, "/users/:id", targetHandler).Name("user")
+	router.Register(http.MethodGet, "/users/:name", dudHandler).Name("member").Name("user")
+
+	_, err := router.URL("member", "name", "baba")
+	ass.True(t, errors.Is(err, mux.ErrUnknownRoute), "skipped route was named", err)
+
+	url, err := router.URL("user", "id", "baba")
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, "/users/baba", url, "wrong url")
+
+	for _, info := range router.Routes() {
+		ass.True(t, info.Name != "member", "skipped route listed by name")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"strings"
+
+	"github.com/go-lean/fun/middle"
+)
+
+// Group registers routes under a shared path prefix, wrapping their handlers in a shared chain.
+type Group struct {
+	router *Router
+	prefix string
+	chain  *middle.Chain
+}
+
+func (r *Router) Group(prefix string, steps ...middle.Step) *Group {
+
+	return &Group{
+		router: r,
+		prefix: joinPath("", prefix),
+		chain:  r.chain.Clone().Add(steps...),
+	}
+}
+
+// Group returns a nested group that inherits the prefix and a clone of the chain of g, so steps
+// added to either one later do not affect the other.
+func (g *Group) Group(prefix string, steps ...middle.Step) *Group {
+
+	return &Group{
+		router: g.router,
+		prefix: joinPath(g.prefix, prefix),
+		chain:  g.chain.Clone().Add(steps...),
+	}
+}
+
+// Use adds steps to the chain of g. They apply to routes registered afterwards.
+func (g *Group) Use(steps ...middle.Step) *Group {
+
+	g.chain.Add(steps...)
+	return g
+}
+
+// Handle registers handler under the group prefix, wrapped in the group chain followed by steps.
+func (g *Group) Handle(method, path string, handler middle.Handler, steps ...middle.Step) *Route {
+
+	chain := g.chain.Clone().Add(steps...)
+
+	route := g.router.newRoute(method, joinPath(g.prefix, path), middle.HTTP(chain.Build(handler)))
+	route.handlerName = handlerName(handler)
+
+	g.router.registerRoute(method, route)
+	return route
+}
+
+// joinPath joins prefix and path with single slashes, keeping the trailing slash of path.
+func joinPath(prefix, path string) string {
+
+	prefix = strings.Trim(prefix, "/")
+	trimmed := strings.Trim(path, "/")
+
+	if trimmed != "" && strings.HasSuffix(path, "/") {
+		trimmed += "/"
+	}
+
+	switch {
+	case prefix == "":
+		return "/" + trimmed
+	case trimmed == "":
+		return "/" + prefix
+	}
+
+	return "/" + prefix + "/" + trimmed
+}
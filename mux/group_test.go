@@ -0,0 +1,147 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/mux"
+	"github.com/go-lean/fun/resp"
+)
+
+func tagStep(tag string) middle.Step {
+
+	return func(r *http.Request, next middle.Handler) resp.Result {
+		response := next(r)
+		response.Payload = fmt.Sprintf("%s>%v", tag, response.Payload)
+
+		return response
+	}
+}
+
+func textHandler(text string) middle.Handler {
+
+	return func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, text, "text/plain")
+	}
+}
+
+// paramsHandler writes name followed by the route params of the request sorted by key, as in
+// "user id=5,tab=posts".
+func paramsHandler(name string) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var pairs []string
+		for key, value := range mux.ParamsFor(r) {
+			pairs = append(pairs, key+"="+value)
+		}
+
+		body := name
+		if len(pairs) > 0 {
+			sort.Strings(pairs)
+			body += " " + strings.Join(pairs, ",")
+		}
+
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// serve passes a request for path to router, applying options to it first.
+func serve(router http.Handler, method, path string, options ...func(r *http.Request)) *httptest.ResponseRecorder {
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, path, nil)
+	for _, option := range options {
+		option(r)
+	}
+
+	router.ServeHTTP(w, r)
+
+	return w
+}
+
+func withHost(host string) func(r *http.Request) {
+
+	return func(r *http.Request) {
+		if host != "" {
+			r.Host = host
+		}
+	}
+}
+
+func withHeader(header http.Header) func(r *http.Request) {
+
+	return func(r *http.Request) {
+		for key, values := range header {
+			for _, value := range values {
+				r.Header.Add(key, value)
+			}
+		}
+	}
+}
+
+func TestGroup_PrefixAndSteps(t *testing.T) {
+
+	router := mux.NewRouter()
+
+	api := router.Group("/api/v1", tagStep("api"))
+	api.Handle(http.MethodGet, "/users/:id", func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, mux.ParamsFor(r)["id"], "text/plain")
+	})
+	api.Handle(http.MethodGet, "/orders", textHandler("orders"), tagStep("route"))
+
+	w := serve(router, http.MethodGet, "/api/v1/users/baba")
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "api>baba", w.Body.String(), "wrong body")
+
+	w = serve(router, http.MethodGet, "/api/v1/orders")
+	ass.Equal(t, "api>route>orders", w.Body.String(), "wrong body")
+
+	w = serve(router, http.MethodGet, "/users/baba")
+	ass.Equal(t, http.StatusNotFound, w.Code, "wrong status code")
+}
+
+func TestGroup_Nested_SiblingsIsolated(t *testing.T) {
+
+	router := mux.NewRouter()
+
+	api := router.Group("/api", tagStep("api"), tagStep("auth"), tagStep("log"))
+	users := api.Group("/users", tagStep("users"))
+	orders := api.Group("orders/", tagStep("orders"))
+	api.Use(tagStep("late"))
+
+	users.Handle(http.MethodGet, "/", textHandler("list"))
+	orders.Handle(http.MethodGet, "/:id", textHandler("order"))
+	api.Handle(http.MethodGet, "/health", textHandler("ok"))
+
+	w := serve(router, http.MethodGet, "/api/users")
+	ass.Equal(t, "api>auth>log>users>list", w.Body.String(), "wrong users body")
+
+	w = serve(router, http.MethodGet, "/api/orders/baba")
+	ass.Equal(t, "api>auth>log>orders>order", w.Body.String(), "wrong orders body")
+
+	w = serve(router, http.MethodGet, "/api/health")
+	ass.Equal(t, "api>auth>log>late>ok", w.Body.String(), "wrong api body")
+}
+
+func TestGroup_RootPrefix(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Group("/").Handle(http.MethodGet, "/", textHandler("root"))
+
+	w := serve(router, http.MethodGet, "/")
+
+	ass.Equal(t, "root", w.Body.String(), "wrong body")
+}
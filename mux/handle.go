@@ -0,0 +1,95 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"net/http"
+
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/resp"
+)
+
+// Use adds router-wide steps. They wrap the routes registered afterwards with Handle, the
+// per-method helpers and groups created afterwards, as well as the responses r writes for
+// requests without a route, such as those of NotFoundHandler. Use is not safe to call while r
+// serves requests.
+func (r *Router) Use(steps ...middle.Step) *Router {
+
+	r.chain.Add(steps...)
+	return r
+}
+
+// Handle registers handler for method and path, wrapped in the router-wide steps followed by
+// steps. The resp.Result it returns is written with resp.Write.
+func (r *Router) Handle(method, path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Group("").Handle(method, path, handler, steps...)
+}
+
+// Get registers handler for GET requests to path, see Handle.
+func (r *Router) Get(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Handle(http.MethodGet, path, handler, steps...)
+}
+
+// Post registers handler for POST requests to path, see Handle.
+func (r *Router) Post(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Handle(http.MethodPost, path, handler, steps...)
+}
+
+// Put registers handler for PUT requests to path, see Handle.
+func (r *Router) Put(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Handle(http.MethodPut, path, handler, steps...)
+}
+
+// Patch registers handler for PATCH requests to path, see Handle.
+func (r *Router) Patch(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Handle(http.MethodPatch, path, handler, steps...)
+}
+
+// Delete registers handler for DELETE requests to path, see Handle.
+func (r *Router) Delete(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return r.Handle(http.MethodDelete, path, handler, steps...)
+}
+
+// Get registers handler for GET requests to path under the group prefix, see Handle.
+func (g *Group) Get(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return g.Handle(http.MethodGet, path, handler, steps...)
+}
+
+// Post registers handler for POST requests to path under the group prefix, see Handle.
+func (g *Group) Post(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return g.Handle(http.MethodPost, path, handler, steps...)
+}
+
+// Put registers handler for PUT requests to path under the group prefix, see Handle.
+func (g *Group) Put(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return g.Handle(http.MethodPut, path, handler, steps...)
+}
+
+// Patch registers handler for PATCH requests to path under the group prefix, see Handle.
+func (g *Group) Patch(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return g.Handle(http.MethodPatch, path, handler, steps...)
+}
+
+// Delete registers handler for DELETE requests to path under the group prefix, see Handle.
+func (g *Group) Delete(path string, handler middle.Handler, steps ...middle.Step) *Route {
+	return g.Handle(http.MethodDelete, path, handler, steps...)
+}
+
+// serveMissWithSteps answers a request without a route like serveMiss, passing the response
+// through the router-wide steps.
+func (r *Router) serveMissWithSteps(w http.ResponseWriter, req *http.Request, t *table, path string) {
+
+	handler := r.chain.Build(func(req *http.Request) resp.Result {
+		buffer := &bufferResponseWriter{header: make(http.Header)}
+		r.serveMiss(buffer, req, t, path)
+
+		return buffer.result()
+	})
+
+	_ = resp.Write(w, req, handler(req))
+}
@@ -0,0 +1,125 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/mux"
+	"github.com/go-lean/fun/resp"
+)
+
+func headerStep(value string) middle.Step {
+
+	return func(r *http.Request, next middle.Handler) resp.Result {
+		res := next(r)
+
+		header := res.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		header.Add("X-Steps", value)
+		res.Header = header
+
+		return res
+	}
+}
+
+func TestRouter_Handle(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Use(tagStep("router"))
+	router.Get("/users/:id", func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, "user "+mux.ParamsFor(r)["id"], "text/plain")
+	}, tagStep("route"))
+
+	router.Post("/users", textHandler("created"))
+	router.Put("/users/:id", textHandler("put"))
+	router.Patch("/users/:id", textHandler("patched"))
+	router.Delete("/users/:id", textHandler("deleted"))
+	router.Group("/api").Get("/ping", textHandler("pong"))
+
+	tc := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/users/5", "router>route>user 5"},
+		{http.MethodPost, "/users", "router>created"},
+		{http.MethodPut, "/users/5", "router>put"},
+		{http.MethodPatch, "/users/5", "router>patched"},
+		{http.MethodDelete, "/users/5", "router>deleted"},
+		{http.MethodGet, "/api/ping", "router>pong"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.method+" "+c.path, func(t *testing.T) {
+			w := serve(router, c.method, c.path)
+
+			ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+			ass.Equal(t, c.body, w.Body.String(), "wrong body")
+		})
+	}
+}
+
+func TestRouter_Handle_HandlerName(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Get("/orders/:id", getOrder)
+
+	ass.Equal(t, "github.com/go-lean/fun/mux_test.getOrder", router.Routes()[0].HandlerName, "wrong handler name")
+}
+
+func TestRouter_Use_WrapsMisses(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Use(headerStep("router"))
+	router.Get("/users", textHandler("users"))
+
+	custom := mux.NewRouter()
+	custom.Use(headerStep("custom"))
+	custom.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not found"}`))
+	}
+
+	tc := []struct {
+		name   string
+		router *mux.Router
+		method string
+		path   string
+		code   int
+		steps  string
+		body   string
+		typ    string
+		allow  string
+	}{
+		{"route", router, http.MethodGet, "/users", http.StatusOK, "router", "users", "text/plain", ""},
+		{"default not found", router, http.MethodGet, "/missing", http.StatusNotFound, "router", "Not Found", "text/plain; charset=utf-8", ""},
+		{"custom not found", custom, http.MethodGet, "/missing", http.StatusNotFound, "custom", `{"error":"not found"}`, "application/json", ""},
+		{"method not allowed", router, http.MethodPost, "/users", http.StatusMethodNotAllowed, "router", "Method Not Allowed", "text/plain; charset=utf-8", "GET, HEAD, OPTIONS"},
+		{"options", router, http.MethodOptions, "/users", http.StatusNoContent, "router", "", "", "GET, HEAD, OPTIONS"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(c.router, c.method, c.path)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			ass.Equal(t, c.steps, w.Header().Get("X-Steps"), "steps not applied")
+			ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			ass.Equal(t, c.typ, w.Header().Get("Content-Type"), "wrong content type")
+			ass.Equal(t, c.allow, w.Header().Get("Allow"), "wrong allow header")
+		})
+	}
+}
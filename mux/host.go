@@ -0,0 +1,200 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// hostRoute dispatches requests whose host matches labels to router.
+type hostRoute struct {
+	pattern string
+	labels  []segment
+	router  *Router
+}
+
+// Host returns the router serving requests whose host matches pattern, creating it on first use.
+// Patterns are dot separated labels, where labels starting with ":" are parameters that may be
+// constrained like path parameters, as in ":tenant.example.com" or ":shard<int>.db.internal".
+// Host parameters are visible through ParamsFor. Hosts match case-insensitively and without their
+// port, and static labels take precedence over parameters, comparing from the rightmost label.
+// Requests whose host matches no pattern are served by r, whose NotFoundHandler and
+// MethodNotAllowedHandler the returned router falls back to.
+func (r *Router) Host(pattern string) *Router {
+
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+
+	labels, err := parseHost(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var sub *Router
+	r.update(func(t *table) {
+		for _, existing := range t.hosts {
+			if existing.pattern == pattern {
+				sub = existing.router
+				return
+			}
+
+			if sameLabels(existing.labels, labels) {
+				r.conflict(&ConflictError{Method: methodAny, Existing: existing.pattern, Pattern: pattern})
+				sub = existing.router
+				return
+			}
+		}
+
+		sub = NewRouter()
+		sub.parent = r
+
+		t.hosts = append(t.hosts, &hostRoute{pattern: pattern, labels: labels, router: sub})
+		sort.SliceStable(t.hosts, func(i, j int) bool {
+			return hostBefore(t.hosts[i].labels, t.hosts[j].labels)
+		})
+	})
+
+	return sub
+}
+
+// serveHost passes req to the router of the first host route matching its host and reports
+// whether there was one.
+func (t *table) serveHost(w http.ResponseWriter, req *http.Request) bool {
+
+	labels := strings.Split(normalizeHost(req.Host), ".")
+	for _, host := range t.hosts {
+		if !matchLabels(host.labels, labels) {
+			continue
+		}
+
+		req, ctx := withParams(req)
+		for i, label := range host.labels {
+			if label.kind == segmentParam {
+				ctx.params.set(label.value, labels[i])
+			}
+		}
+
+		host.router.ServeHTTP(w, req)
+
+		return true
+	}
+
+	return false
+}
+
+func parseHost(pattern string) ([]segment, error) {
+
+	if pattern == "" {
+		return nil, fmt.Errorf("empty host pattern")
+	}
+
+	tokens := strings.Split(pattern, ".")
+	labels := make([]segment, len(tokens))
+
+	for i, token := range tokens {
+		if token == "" {
+			return nil, fmt.Errorf("empty label in host %q", pattern)
+		}
+
+		label, err := parseSegment(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w in host %q", err, pattern)
+		}
+
+		if label.kind == segmentCatchAll {
+			return nil, fmt.Errorf("catch-all in host %q", pattern)
+		}
+
+		if label.optional {
+			return nil, fmt.Errorf("optional label in host %q", pattern)
+		}
+
+		labels[i] = label
+	}
+
+	return labels, nil
+}
+
+// normalizeHost lowercases host and strips its port and a trailing dot.
+func normalizeHost(host string) string {
+
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func matchLabels(pattern []segment, labels []string) bool {
+
+	if len(pattern) != len(labels) {
+		return false
+	}
+
+	for i, label := range pattern {
+		switch {
+		case label.kind == segmentStatic:
+			if label.value != labels[i] {
+				return false
+			}
+		case labels[i] == "":
+			return false
+		case label.constraint != nil && !label.constraint.match(labels[i]):
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameLabels reports whether a and b match the same hosts, ignoring parameter names.
+func sameLabels(a, b []segment) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if labelRank(a[i]) != labelRank(b[i]) || a[i].kind == segmentStatic && a[i].value != b[i].value {
+			return false
+		}
+
+		if a[i].constraint != nil && a[i].constraint.source != b[i].constraint.source {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostBefore orders host patterns by the first label from the right where they differ in rank.
+func hostBefore(a, b []segment) bool {
+
+	for i, j := len(a)-1, len(b)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if ra, rb := labelRank(a[i]), labelRank(b[j]); ra != rb {
+			return ra < rb
+		}
+	}
+
+	return false
+}
+
+// labelRank orders static labels before constrained and then unconstrained parameters.
+func labelRank(label segment) int {
+
+	switch {
+	case label.kind == segmentStatic:
+		return 0
+	case label.constraint != nil:
+		return 1
+	}
+
+	return 2
+}
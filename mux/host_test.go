@@ -0,0 +1,93 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_Host(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", paramsHandler("default"))
+	router.Host("api.example.com").Register(http.MethodGet, "/users/:id", paramsHandler("api"))
+	router.Host(":tenant.example.com").Register(http.MethodGet, "/users/:id", paramsHandler("tenant"))
+	router.Host(":n<int>.example.com").Register(http.MethodGet, "/users/:id", paramsHandler("shard"))
+
+	tc := []struct {
+		name string
+		host string
+		code int
+		body string
+	}{
+		{"static", "api.example.com", http.StatusOK, "api id=5"},
+		{"port and case", "API.Example.com:8080", http.StatusOK, "api id=5"},
+		{"param", "baba.example.com", http.StatusOK, "tenant id=5,tenant=baba"},
+		{"constrained before param", "42.example.com", http.StatusOK, "shard id=5,n=42"},
+		{"unmatched host", "example.org", http.StatusOK, "default id=5"},
+		{"label count differs", "a.b.example.com", http.StatusOK, "default id=5"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/users/5", withHost(c.host))
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			ass.Equal(t, c.body, w.Body.String(), "wrong body")
+		})
+	}
+}
+
+func TestRouter_Host_SameRouter(t *testing.T) {
+
+	router := mux.NewRouter()
+
+	ass.Equal(t, router.Host("api.example.com"), router.Host("API.example.com."), "expected the same router")
+}
+
+func TestRouter_Host_FallsBackToDefaultHandlers(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	router.Host(":tenant.example.com").Register(http.MethodGet, "/", dudHandler)
+
+	w := serve(router, http.MethodGet, "/missing", withHost("baba.example.com"))
+
+	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
+}
+
+func TestRouter_Host_Conflict(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.ErrorOnConflict
+
+	tenants := router.Host(":tenant.example.com")
+	shadowed := router.Host(":name.example.com")
+
+	ass.Equal(t, tenants, shadowed, "expected the registered router")
+	ass.Equal(t, "mux: route * :name.example.com conflicts with registered route * :tenant.example.com",
+		router.Err().Error(), "wrong error")
+}
+
+func TestRouter_Host_InvalidPattern_Panics(t *testing.T) {
+
+	for _, pattern := range []string{"", "api..example.com", "*rest.example.com", ":.example.com"} {
+		action := func() {
+			mux.NewRouter().Host(pattern)
+		}
+
+		ass.Panics(t, action, "failed to panic on invalid host pattern", pattern)
+	}
+}
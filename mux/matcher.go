@@ -0,0 +1,150 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type (
+	matcherKind int
+
+	// Matcher restricts a route to requests with a header, query parameter or content type. Routes
+	// with the same method and pattern are tried in order of their number of matchers and then in
+	// registration order, so a route without matchers is the fallback of those with matchers.
+	Matcher struct {
+		kind   matcherKind
+		key    string
+		values []string
+	}
+
+	// selector carries what a tree lookup matches besides the path. A nil request matches any
+	// route and anyType skips content type matchers.
+	selector struct {
+		fold    bool
+		req     *http.Request
+		anyType bool
+	}
+)
+
+const (
+	matchHeader matcherKind = iota
+	matchQuery
+	matchContentType
+)
+
+// Header matches requests with a key header equal to value or, for an empty value, with any key
+// header.
+func Header(key, value string) Matcher {
+	return Matcher{kind: matchHeader, key: http.CanonicalHeaderKey(key), values: []string{value}}
+}
+
+// Query matches requests with a key query parameter equal to value or, for an empty value, with any
+// key query parameter.
+func Query(key, value string) Matcher {
+	return Matcher{kind: matchQuery, key: key, values: []string{value}}
+}
+
+// ContentType matches requests whose Content-Type has one of mediaTypes, ignoring parameters such
+// as the charset. A "type/*" media type matches any subtype. Requests to a route that only differ
+// in their content type are answered with 415 Unsupported Media Type.
+func ContentType(mediaTypes ...string) Matcher {
+
+	values := make([]string, len(mediaTypes))
+	for i, mediaType := range mediaTypes {
+		values[i] = strings.ToLower(strings.TrimSpace(mediaType))
+	}
+
+	return Matcher{kind: matchContentType, values: values}
+}
+
+// String describes m, as in "header Accept-Version=2" or "content-type application/json".
+func (m Matcher) String() string {
+
+	switch m.kind {
+	case matchHeader:
+		return "header " + m.key + "=" + m.values[0]
+	case matchQuery:
+		return "query " + m.key + "=" + m.values[0]
+	}
+
+	return "content-type " + strings.Join(m.values, ", ")
+}
+
+func (m Matcher) match(req *http.Request) bool {
+
+	switch m.kind {
+	case matchHeader:
+		return matchValues(req.Header[m.key], m.values[0])
+	case matchQuery:
+		return matchValues(req.URL.Query()[m.key], m.values[0])
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	for _, value := range m.values {
+		if value == mediaType || strings.HasSuffix(value, "/*") && strings.HasPrefix(mediaType, value[:len(value)-1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchValues(values []string, want string) bool {
+
+	if want == "" {
+		return len(values) > 0
+	}
+
+	for _, value := range values {
+		if strings.TrimSpace(value) == want {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accepts reports whether the matchers of rt match the request of sel.
+func (rt *Route) accepts(sel *selector) bool {
+
+	if sel.req == nil {
+		return true
+	}
+
+	for _, m := range rt.matchers {
+		if m.kind == matchContentType && sel.anyType {
+			continue
+		}
+
+		if !m.match(sel.req) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matcherKey identifies the matcher set of rt regardless of order.
+func (rt *Route) matcherKey() string {
+
+	keys := make([]string, len(rt.matchers))
+	for i, m := range rt.matchers {
+		keys[i] = m.String()
+	}
+
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
@@ -0,0 +1,105 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_Matchers(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodPatch, "/users/:id", paramsHandler("merge"), mux.ContentType("application/merge-patch+json"))
+	router.Register(http.MethodPatch, "/users/:id", paramsHandler("json-patch"), mux.ContentType("application/json-patch+json"))
+	router.Register(http.MethodGet, "/items", paramsHandler("v1"))
+	router.Register(http.MethodGet, "/items", paramsHandler("v2 header"), mux.Header("Accept-Version", "2"))
+	router.Register(http.MethodGet, "/items", paramsHandler("v2 query"), mux.Query("v", "2"))
+	router.Register(http.MethodGet, "/items", paramsHandler("v2 both"), mux.Header("Accept-Version", "2"), mux.Query("v", "2"))
+	router.Register(http.MethodPost, "/uploads", paramsHandler("image"), mux.ContentType("image/*"))
+	router.Register(http.MethodGet, "/beta", paramsHandler("beta"), mux.Header("X-Beta", ""))
+	router.Register(http.MethodGet, "/:page", paramsHandler("page"))
+	router.Register(http.MethodGet, "/versioned/items", paramsHandler("header"), mux.Header("Accept-Version", "2"))
+	router.Register(http.MethodGet, "/versioned/items", paramsHandler("query"), mux.Query("v", "2"))
+
+	tc := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		code   int
+		body   string
+	}{
+		{"merge patch", http.MethodPatch, "/users/1", http.Header{"Content-Type": {"application/merge-patch+json; charset=utf-8"}}, http.StatusOK, "merge id=1"},
+		{"json patch", http.MethodPatch, "/users/1", http.Header{"Content-Type": {"application/json-patch+json"}}, http.StatusOK, "json-patch id=1"},
+		{"unsupported type", http.MethodPatch, "/users/1", http.Header{"Content-Type": {"application/json"}}, http.StatusUnsupportedMediaType, ""},
+		{"missing type", http.MethodPatch, "/users/1", nil, http.StatusUnsupportedMediaType, ""},
+		{"fallback", http.MethodGet, "/items", nil, http.StatusOK, "v1"},
+		{"header", http.MethodGet, "/items", http.Header{"Accept-Version": {"2"}}, http.StatusOK, "v2 header"},
+		{"other header value", http.MethodGet, "/items", http.Header{"Accept-Version": {"3"}}, http.StatusOK, "v1"},
+		{"query", http.MethodGet, "/items?v=2", nil, http.StatusOK, "v2 query"},
+		{"most matchers first", http.MethodGet, "/items?v=2", http.Header{"Accept-Version": {"2"}}, http.StatusOK, "v2 both"},
+		{"wildcard type", http.MethodPost, "/uploads", http.Header{"Content-Type": {"image/png"}}, http.StatusOK, "image"},
+		{"present header", http.MethodGet, "/beta", http.Header{"X-Beta": {"yes"}}, http.StatusOK, "beta"},
+		{"backtracks to param", http.MethodGet, "/beta", nil, http.StatusOK, "page page=beta"},
+		{"method not allowed", http.MethodPut, "/users/1", nil, http.StatusMethodNotAllowed, ""},
+		{"missing header", http.MethodGet, "/versioned/items", nil, http.StatusNotFound, ""},
+		{"other query value", http.MethodGet, "/versioned/items?v=1", nil, http.StatusNotFound, ""},
+		{"matched elsewhere", http.MethodPost, "/versioned/items?v=2", nil, http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(router, c.method, c.path, withHeader(c.header))
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			if c.code == http.StatusOK {
+				ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			}
+		})
+	}
+}
+
+func TestRouter_Matchers_Conflict(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Conflicts = mux.ErrorOnConflict
+
+	router.Register(http.MethodGet, "/items", dudHandler, mux.Header("Accept-Version", "2"), mux.Query("v", "2"))
+	router.Register(http.MethodGet, "/items", dudHandler, mux.Query("v", "2"), mux.Header("accept-version", "2"))
+
+	ass.Equal(t, "mux: route GET /items conflicts with registered route GET /items", router.Err().Error(), "wrong error")
+}
+
+func TestRouter_UnsupportedMediaTypeHandler(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.UnsupportedMediaTypeHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	router.Register(http.MethodPost, "/items", dudHandler, mux.ContentType("application/json"))
+
+	w := serve(router, http.MethodPost, "/items")
+
+	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
+}
+
+func TestRouter_Routes_Matchers(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/items", dudHandler, mux.Header("accept-version", "2"), mux.ContentType("Application/JSON", "text/*"))
+
+	routes := router.Routes()
+
+	ass.Equal(t, "header Accept-Version=2|content-type application/json, text/*", strings.Join(routes[0].Matchers, "|"), "wrong matchers")
+}
@@ -0,0 +1,88 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	errMountCatchAllFmt = "mount prefix can not contain a catch-all: %q"
+	errMountOptionalFmt = "mount prefix can not contain optional segments: %q"
+
+	// methodAny is the method mounts are listed under, as they serve every method.
+	methodAny = "*"
+)
+
+// Mount serves every request under prefix with handler, for any method, after stripping the
+// prefix from the request path. The prefix may contain parameters, which stay visible through
+// ParamsFor. Routes registered on r take precedence over mounts. A mounted *Router without its own
+// NotFoundHandler or MethodNotAllowedHandler falls back to those of r.
+func (r *Router) Mount(prefix string, handler http.Handler) {
+
+	segments, err := parsePattern(strings.TrimRight(prefix, "/"))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	params := 0
+	for _, seg := range segments {
+		switch seg.kind {
+		case segmentCatchAll:
+			panic(fmt.Sprintf(errMountCatchAllFmt, prefix))
+		case segmentParam:
+			if seg.optional {
+				panic(fmt.Sprintf(errMountOptionalFmt, prefix))
+			}
+
+			params++
+		}
+	}
+
+	if sub, ok := handler.(*Router); ok {
+		sub.parent = r
+	}
+
+	route := &Route{
+		router:      r,
+		method:      methodAny,
+		pattern:     "/" + strings.TrimPrefix(prefix, "/"),
+		handlerName: handlerName(handler),
+		segments:    append(segments, segment{kind: segmentCatchAll}),
+		handler: routeHandler{
+			handler: stripSegments(len(segments), handler),
+			params:  params,
+		},
+	}
+
+	r.update(func(t *table) {
+		t.mounts = r.insert(t, t.mounts, route)
+	})
+}
+
+// stripSegments returns a handler serving requests with the first count path segments removed.
+// A trailing slash is kept, so handlers such as http.FileServer can tell directories apart.
+func stripSegments(count int, handler http.Handler) http.HandlerFunc {
+
+	return func(w http.ResponseWriter, req *http.Request) {
+
+		escaped := req.URL.EscapedPath()
+
+		rest := strings.TrimPrefix(escaped, "/")
+		for i := 0; i < count && rest != ""; i++ {
+			_, rest, _ = strings.Cut(rest, "/")
+		}
+
+		prefix := strings.TrimSuffix(escaped[:len(escaped)-len(rest)], "/")
+
+		stripped := withPath(withMountPrefix(req, prefix), "/"+rest)
+		handler.ServeHTTP(w, stripped)
+	}
+}
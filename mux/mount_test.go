@@ -0,0 +1,139 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+var pathHandler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(r.URL.Path))
+}
+
+func TestRouter_Mount_StripsPrefix(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Mount("/static", pathHandler)
+
+	tc := []struct {
+		path     string
+		stripped string
+	}{
+		{"/static/css/app.css", "/css/app.css"},
+		{"/static/css/", "/css/"},
+		{"/static/", "/"},
+		{"/static", "/"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.path, func(t *testing.T) {
+			for _, method := range []string{http.MethodGet, http.MethodPost, "PROPFIND"} {
+				w := serve(router, method, c.path)
+
+				ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+				ass.Equal(t, c.stripped, w.Body.String(), "wrong stripped path")
+			}
+		})
+	}
+}
+
+func TestRouter_Mount_RoutesTakePrecedence(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Mount("/files", pathHandler)
+	router.Register(http.MethodGet, "/files/readme", targetHandler)
+
+	w := serve(router, http.MethodGet, "/files/readme")
+	ass.Equal(t, "baba", w.Body.String(), "route did not take precedence")
+
+	w = serve(router, http.MethodPost, "/files/readme")
+	ass.Equal(t, "/readme", w.Body.String(), "mount did not serve other methods")
+}
+
+func TestRouter_Mount_SubRouterWithParams(t *testing.T) {
+
+	tenants := mux.NewRouter()
+	tenants.Register(http.MethodGet, "/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		params := mux.ParamsFor(r)
+		_, _ = w.Write([]byte(params["tenant"] + ":" + params["id"]))
+	})
+
+	router := mux.NewRouter()
+	router.Mount("/tenants/:tenant", tenants)
+
+	w := serve(router, http.MethodGet, "/tenants/baba/users/42")
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "baba:42", w.Body.String(), "wrong params")
+}
+
+func TestRouter_Mount_SubRouterFallsBackToParentHandlers(t *testing.T) {
+
+	sub := mux.NewRouter()
+	sub.Register(http.MethodGet, "/users", dudHandler)
+
+	router := mux.NewRouter()
+	router.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+	router.MethodNotAllowedHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}
+	router.Mount("/api", sub)
+
+	w := serve(router, http.MethodGet, "/api/orders")
+	ass.Equal(t, http.StatusTeapot, w.Code, "parent not found handler not used")
+
+	w = serve(router, http.MethodPost, "/api/users")
+	ass.Equal(t, http.StatusConflict, w.Code, "parent method not allowed handler not used")
+
+	sub.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}
+
+	w = serve(router, http.MethodGet, "/api/orders")
+	ass.Equal(t, http.StatusGone, w.Code, "own not found handler not used")
+}
+
+func TestRouter_DefaultHandlers_CanBeWrapped(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users", dudHandler)
+
+	prevNotFound, prevNotAllowed := router.NotFoundHandler, router.MethodNotAllowedHandler
+	router.NotFoundHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Wrapped", "yes")
+		prevNotFound(w, r)
+	}
+	router.MethodNotAllowedHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Wrapped", "yes")
+		prevNotAllowed(w, r)
+	}
+
+	w := serve(router, http.MethodGet, "/orders")
+	ass.Equal(t, http.StatusNotFound, w.Code, "wrong status code")
+	ass.Equal(t, "yes", w.Header().Get("X-Wrapped"), "wrapper not used")
+
+	w = serve(router, http.MethodPost, "/users")
+	ass.Equal(t, http.StatusMethodNotAllowed, w.Code, "wrong status code")
+	ass.Equal(t, "yes", w.Header().Get("X-Wrapped"), "wrapper not used")
+}
+
+func TestRouter_Mount_CatchAllPrefix_Panics(t *testing.T) {
+
+	router := mux.NewRouter()
+	action := func() {
+		router.Mount("/static/*path", pathHandler)
+	}
+
+	ass.Panics(t, action, "failed to panic on catch-all mount prefix")
+}
@@ -0,0 +1,137 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_OptionalSegments(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/reports/:year<int>/:month<int>?=1/:day?", paramsHandler("report"))
+	router.Register(http.MethodGet, "/reports/latest", paramsHandler("latest"))
+	router.Register(http.MethodGet, "/reports/:year/summary", paramsHandler("summary"))
+	router.Register(http.MethodGet, "/reports/:name", paramsHandler("named"))
+	router.Register(http.MethodGet, "/:page?=home", paramsHandler("page"))
+
+	tc := []struct {
+		name string
+		path string
+		code int
+		body string
+	}{
+		{"all present", "/reports/2023/5/17", http.StatusOK, "report day=17,month=5,year=2023"},
+		{"default", "/reports/2023/5", http.StatusOK, "report day=,month=5,year=2023"},
+		{"defaults", "/reports/2023", http.StatusOK, "report day=,month=1,year=2023"},
+		{"trailing slash", "/reports/2023/", http.StatusOK, "report day=,month=1,year=2023"},
+		{"static wins", "/reports/latest", http.StatusOK, "latest"},
+		{"static below param wins", "/reports/2023/summary", http.StatusOK, "summary year=2023"},
+		{"constraint falls through", "/reports/q1", http.StatusOK, "named name=q1"},
+		{"optional constraint", "/reports/2023/may", http.StatusNotFound, ""},
+		{"too long", "/reports/2023/5/17/x", http.StatusNotFound, ""},
+		{"root default", "/", http.StatusOK, "page page=home"},
+		{"root value", "/about", http.StatusOK, "page page=about"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, c.path)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			if c.code == http.StatusOK {
+				ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			}
+		})
+	}
+}
+
+func TestRouter_OptionalSegments_Conflicts(t *testing.T) {
+
+	tc := []struct {
+		name     string
+		existing string
+		pattern  string
+		path     string
+	}{
+		{"without the optional segment", "/reports/:year", "/reports/:y/:month?", "/reports/2023"},
+		{"with the optional segment", "/reports/:year/:month", "/reports/:y/:m?", "/reports/2023/5"},
+		{"registered first", "/reports/:year/:month?", "/reports/:y", "/reports/2023"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Conflicts = mux.ErrorOnConflict
+
+			router.Register(http.MethodGet, c.existing, paramsHandler("existing"))
+			router.Register(http.MethodGet, c.pattern, paramsHandler("shadowed"))
+
+			ass.Equal(t, false, router.Err() == nil, "expected a conflict")
+			ass.Equal(t, 1, len(router.Routes()), "conflicting route was listed")
+			body := serve(router, http.MethodGet, c.path).Body.String()
+			ass.True(t, strings.HasPrefix(body, "existing "), "wrong handler", body)
+		})
+	}
+}
+
+func TestRouter_OptionalSegments_URL(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/reports/:year/:month?/:day?", dudHandler).Name("report")
+
+	tc := []struct {
+		params []string
+		url    string
+	}{
+		{[]string{"year", "2023"}, "/reports/2023"},
+		{[]string{"year", "2023", "month", "5"}, "/reports/2023/5"},
+		{[]string{"year", "2023", "month", "5", "day", "17"}, "/reports/2023/5/17"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.url, func(t *testing.T) {
+			url, err := router.URL("report", c.params...)
+
+			ass.Equal(t, nil, err, "unexpected error")
+			ass.Equal(t, c.url, url, "wrong url")
+		})
+	}
+
+	_, err := router.URL("report", "year", "2023", "day", "17")
+	ass.Equal(t, false, err == nil, "expected an error for a day without a month")
+}
+
+func TestRouter_OptionalSegments_Invalid_Panics(t *testing.T) {
+
+	patterns := []string{
+		"/reports/:year?/:month",
+		"/reports/:year?/summary",
+		"/reports/:year?/",
+		"/reports/:year?/*rest",
+		"/reports/:year?x",
+		"/reports/:year<int>?=now",
+		"/reports/:?",
+	}
+
+	for _, pattern := range patterns {
+		action := func() {
+			mux.NewRouter().Register(http.MethodGet, pattern, dudHandler)
+		}
+
+		ass.Panics(t, action, "failed to panic on invalid pattern", pattern)
+	}
+
+	ass.Panics(t, func() { mux.NewRouter().Mount("/reports/:year?", dudHandler) }, "failed to panic on optional mount prefix")
+	ass.Panics(t, func() { mux.NewRouter().Host(":tenant?.example.com") }, "failed to panic on optional host label")
+}
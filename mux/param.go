@@ -0,0 +1,182 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-lean/fun/middle"
+	"github.com/go-lean/fun/resp"
+)
+
+// ParamError reports a route parameter that is missing or can not be parsed into the requested
+// type. Its Result is a 400 problem.
+type ParamError struct {
+	Name  string
+	Value string
+	Type  string
+	Err   error
+}
+
+const errUnsupportedParamFmt = "mux: unsupported parameter type %s"
+
+var ErrMissingParam = errors.New("mux: missing route parameter")
+
+// Param parses the route parameter name of r into a T, which may be any integer, unsigned integer,
+// float or bool type, a string, a time.Duration, a time.Time in RFC 3339 format or any type whose
+// pointer implements encoding.TextUnmarshaler. Missing or empty parameters and values that do not
+// parse are reported as a *ParamError. Other types panic.
+func Param[T any](r *http.Request, name string) (T, error) {
+
+	var value T
+
+	raw := ParamValue(r, name)
+	if raw == "" {
+		return value, &ParamError{Name: name, Type: typeName(&value), Err: ErrMissingParam}
+	}
+
+	if err := parseParam(&value, raw); err != nil {
+		return value, &ParamError{Name: name, Value: raw, Type: typeName(&value), Err: err}
+	}
+
+	return value, nil
+}
+
+// ParamOr returns the route parameter name of r parsed like Param, or fallback when it is missing,
+// empty or invalid.
+func ParamOr[T any](r *http.Request, name string, fallback T) T {
+
+	value, err := Param[T](r, name)
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// MustParam returns the route parameter name of r parsed like Param and panics with the
+// *ParamError otherwise. The ParamErrors step turns those panics into 400 responses.
+func MustParam[T any](r *http.Request, name string) T {
+
+	value, err := Param[T](r, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// ParamErrors is a middle.Step that answers requests whose handler panicked in MustParam with the
+// Result of the *ParamError. Other panics are passed on.
+func ParamErrors(r *http.Request, next middle.Handler) (res resp.Result) {
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		err, ok := recovered.(*ParamError)
+		if !ok {
+			panic(recovered)
+		}
+
+		res = err.Result()
+	}()
+
+	return next(r)
+}
+
+func (e *ParamError) Error() string {
+
+	if errors.Is(e.Err, ErrMissingParam) {
+		return fmt.Sprintf("%v %q", e.Err, e.Name)
+	}
+
+	return fmt.Sprintf("mux: invalid route parameter %q: %v", e.Name, e.Err)
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// Result returns a 400 problem naming the parameter under the "errors" member.
+func (e *ParamError) Result() resp.Result {
+
+	message := fmt.Sprintf("invalid value %q, want %s", e.Value, e.Type)
+	if errors.Is(e.Err, ErrMissingParam) {
+		message = "missing value"
+	}
+
+	problem := resp.NewProblem(http.StatusBadRequest, "invalid route parameter "+strconv.Quote(e.Name))
+	problem.With("errors", []resp.FieldError{{Field: e.Name, Message: message}})
+
+	return problem.Result()
+}
+
+// parseParam parses raw into the value pointed to by target.
+func parseParam(target any, raw string) error {
+
+	switch target := target.(type) {
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		*target = d
+
+		return err
+	case encoding.TextUnmarshaler:
+		return target.UnmarshalText([]byte(raw))
+	}
+
+	value := reflect.ValueOf(target).Elem()
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+	default:
+		panic(fmt.Sprintf(errUnsupportedParamFmt, value.Type()))
+	}
+
+	return nil
+}
+
+func typeName(target any) string {
+	return reflect.TypeOf(target).Elem().String()
+}
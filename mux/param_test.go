@@ -0,0 +1,166 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+	"github.com/go-lean/fun/resp"
+)
+
+type color string
+
+func (c *color) UnmarshalText(text []byte) error {
+
+	switch string(text) {
+	case "red", "green", "blue":
+		*c = color(text)
+		return nil
+	}
+
+	return fmt.Errorf("unknown color %q", text)
+}
+
+func paramRequest(params map[string]string) *http.Request {
+	return mux.SetParams(httptest.NewRequest(http.MethodGet, "/", nil), params)
+}
+
+func TestParam(t *testing.T) {
+
+	r := paramRequest(map[string]string{
+		"int":      "-42",
+		"uint":     "42",
+		"float":    "4.2",
+		"bool":     "true",
+		"duration": "1m30s",
+		"time":     "2023-05-01T10:00:00Z",
+		"color":    "red",
+		"name":     "baba",
+	})
+
+	id, err := mux.Param[int](r, "int")
+	ass.Equal(t, nil, err, "unexpected int error")
+	ass.Equal(t, -42, id, "wrong int")
+
+	u, err := mux.Param[uint8](r, "uint")
+	ass.Equal(t, nil, err, "unexpected uint error")
+	ass.Equal(t, uint8(42), u, "wrong uint")
+
+	f, err := mux.Param[float64](r, "float")
+	ass.Equal(t, nil, err, "unexpected float error")
+	ass.Equal(t, 4.2, f, "wrong float")
+
+	b, err := mux.Param[bool](r, "bool")
+	ass.Equal(t, nil, err, "unexpected bool error")
+	ass.Equal(t, true, b, "wrong bool")
+
+	d, err := mux.Param[time.Duration](r, "duration")
+	ass.Equal(t, nil, err, "unexpected duration error")
+	ass.Equal(t, 90*time.Second, d, "wrong duration")
+
+	at, err := mux.Param[time.Time](r, "time")
+	ass.Equal(t, nil, err, "unexpected time error")
+	ass.Equal(t, time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC), at, "wrong time")
+
+	c, err := mux.Param[color](r, "color")
+	ass.Equal(t, nil, err, "unexpected color error")
+	ass.Equal(t, color("red"), c, "wrong color")
+
+	name, err := mux.Param[string](r, "name")
+	ass.Equal(t, nil, err, "unexpected string error")
+	ass.Equal(t, "baba", name, "wrong string")
+}
+
+func TestParam_Errors(t *testing.T) {
+
+	r := paramRequest(map[string]string{"id": "abc", "small": "300", "empty": "", "color": "pink"})
+
+	tc := []struct {
+		name    string
+		parse   func() error
+		message string
+	}{
+		{"invalid int", func() error { _, err := mux.Param[int](r, "id"); return err }, `mux: invalid route parameter "id": strconv.ParseInt: parsing "abc": invalid syntax`},
+		{"out of range", func() error { _, err := mux.Param[uint8](r, "small"); return err }, `mux: invalid route parameter "small": strconv.ParseUint: parsing "300": value out of range`},
+		{"missing", func() error { _, err := mux.Param[int](r, "missing"); return err }, `mux: missing route parameter "missing"`},
+		{"empty", func() error { _, err := mux.Param[int](r, "empty"); return err }, `mux: missing route parameter "empty"`},
+		{"unmarshaler", func() error { _, err := mux.Param[color](r, "color"); return err }, `mux: invalid route parameter "color": unknown color "pink"`},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.parse()
+
+			var paramErr *mux.ParamError
+			ass.True(t, errors.As(err, &paramErr), "expected a *ParamError")
+			ass.Equal(t, c.message, err.Error(), "wrong message")
+			ass.Equal(t, http.StatusBadRequest, paramErr.Result().Code, "wrong result code")
+		})
+	}
+}
+
+func TestParam_UnsupportedType_Panics(t *testing.T) {
+
+	action := func() {
+		_, _ = mux.Param[[]int](paramRequest(map[string]string{"id": "1"}), "id")
+	}
+
+	ass.Panics(t, action, "failed to panic on unsupported type")
+}
+
+func TestParamOr(t *testing.T) {
+
+	r := paramRequest(map[string]string{"page": "3", "size": "big"})
+
+	ass.Equal(t, 3, mux.ParamOr(r, "page", 1), "wrong page")
+	ass.Equal(t, 20, mux.ParamOr(r, "size", 20), "invalid value did not fall back")
+	ass.Equal(t, 20, mux.ParamOr(r, "limit", 20), "missing value did not fall back")
+}
+
+func TestMustParam_ParamErrors(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Use(mux.ParamErrors)
+	router.Get("/users/:id", func(r *http.Request) resp.Result {
+		return resp.New(http.StatusOK, fmt.Sprint(mux.MustParam[int](r, "id")), "text/plain")
+	})
+
+	w := serve(router, http.MethodGet, "/users/42")
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "42", w.Body.String(), "wrong body")
+
+	w = serve(router, http.MethodGet, "/users/abc")
+
+	ass.Equal(t, http.StatusBadRequest, w.Code, "wrong status code")
+	ass.Equal(t, resp.TypeProblemJSON, w.Header().Get("Content-Type"), "wrong content type")
+	ass.True(t, strings.Contains(w.Body.String(), `"errors":[{"field":"id","message":"invalid value \"abc\", want int"}]`), "wrong body", w.Body.String())
+}
+
+func TestParamErrors_PassesOtherPanics(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Use(mux.ParamErrors)
+	router.Get("/", func(r *http.Request) resp.Result {
+		panic("baba")
+	})
+
+	action := func() {
+		serve(router, http.MethodGet, "/")
+	}
+
+	ass.Panics(t, action, "failed to pass on the panic")
+}
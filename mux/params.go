@@ -0,0 +1,169 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type (
+	// params are the route parameters of a request in the order they were matched. Later entries
+	// shadow earlier ones, so the parameters of a route win over those of the prefix or host it is
+	// mounted under.
+	params struct {
+		entries []paramEntry
+	}
+
+	paramEntry struct {
+		name  string
+		value string
+	}
+
+	// paramsContext carries the params of a request. Its entries start out in inline, so routing a
+	// request allocates only the context and the shallow request copy unless it has more than
+	// inlineParams parameters.
+	paramsContext struct {
+		context.Context
+		params params
+		inline [inlineParams]paramEntry
+	}
+)
+
+const inlineParams = 4
+
+var keyRouteParams = struct{}{}
+
+// SetParams returns a shallow copy of r carrying vars as its route parameters, replacing any set
+// before.
+func SetParams(r *http.Request, vars map[string]string) *http.Request {
+
+	p := &params{entries: make([]paramEntry, 0, len(vars))}
+	for name, value := range vars {
+		p.set(name, value)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), keyRouteParams, p))
+}
+
+// ParamsFor returns a copy of the route parameters of r, or nil when there are none.
+func ParamsFor(r *http.Request) map[string]string {
+
+	p := paramsOf(r)
+	if p == nil || len(p.entries) == 0 {
+		return nil
+	}
+
+	vars := make(map[string]string, len(p.entries))
+	for _, entry := range p.entries {
+		vars[entry.name] = entry.value
+	}
+
+	return vars
+}
+
+// ParamValue returns the route parameter name of r, or "" when there is none. Unlike ParamsFor it
+// does not allocate.
+func ParamValue(r *http.Request, name string) string {
+
+	p := paramsOf(r)
+	if p == nil {
+		return ""
+	}
+
+	value, _ := p.get(name)
+	return value
+}
+
+func paramsOf(r *http.Request) *params {
+
+	p, _ := r.Context().Value(keyRouteParams).(*params)
+	return p
+}
+
+func (p *params) get(name string) (string, bool) {
+
+	for i := len(p.entries) - 1; i >= 0; i-- {
+		if p.entries[i].name == name {
+			return p.entries[i].value, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *params) set(name, value string) {
+	p.entries = append(p.entries, paramEntry{name: name, value: value})
+}
+
+// withParams returns a shallow copy of req carrying a params context that starts with the
+// parameters already set on req.
+func withParams(req *http.Request) (*http.Request, *paramsContext) {
+
+	ctx := &paramsContext{Context: req.Context()}
+	ctx.params.entries = ctx.inline[:0]
+
+	if existing := paramsOf(req); existing != nil {
+		ctx.params.entries = append(ctx.params.entries, existing.entries...)
+	}
+
+	return req.WithContext(ctx), ctx
+}
+
+func (c *paramsContext) Value(key any) any {
+
+	if key == keyRouteParams {
+		return &c.params
+	}
+
+	return c.Context.Value(key)
+}
+
+// appendParams adds the values of the named parameter and catch-all segments of a route matching
+// the escaped path to p.
+func (r *Router) appendParams(p *params, path string, segments []segment) {
+
+	rest, more := path, path != ""
+	for _, seg := range segments {
+		switch {
+		case seg.kind == segmentCatchAll:
+			if seg.value != "" {
+				p.set(seg.value, r.paramValue(rest))
+			}
+
+			return
+		case !more:
+			// Only optional parameters are left when the path ends early.
+			p.set(seg.value, seg.fallback)
+			continue
+		}
+
+		token, next, found := strings.Cut(rest, "/")
+		if seg.kind == segmentParam {
+			p.set(seg.value, r.paramValue(token))
+		}
+
+		rest, more = next, found
+	}
+}
+
+// paramValue unescapes the slash separated segments of value one by one unless r.RawParams.
+func (r *Router) paramValue(value string) string {
+
+	if r.RawParams || strings.IndexByte(value, '%') < 0 {
+		return value
+	}
+
+	segments := strings.Split(value, "/")
+	for i, seg := range segments {
+		segments[i], _ = unescapeSegment(seg)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,148 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_ParamLookup_AllocatesContextAndRequest(t *testing.T) {
+
+	var id, item string
+
+	router := mux.NewRouter()
+	registerBenchmarkRoutes(router.Register, 100)
+	router.Register(http.MethodGet, "/users/:id/items/:item", func(w http.ResponseWriter, r *http.Request) {
+		id, item = mux.ParamValue(r, "id"), mux.ParamValue(r, "item")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/baba/items/flag", nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		router.ServeHTTP(w, r)
+	})
+
+	ass.Equal(t, 2.0, allocs, "param lookup allocated more than the context and the request copy")
+	ass.Equal(t, "baba", id, "wrong id")
+	ass.Equal(t, "flag", item, "wrong item")
+}
+
+func TestRouter_Params_NotReusedAcrossRequests(t *testing.T) {
+
+	var params map[string]string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		params = mux.ParamsFor(r)
+	}
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", handler)
+	router.Register(http.MethodGet, "/teams/:team", handler)
+	router.Register(http.MethodGet, "/health", handler)
+
+	serve(router, http.MethodGet, "/users/5")
+	ass.Equal(t, "5", params["id"], "wrong id")
+
+	serve(router, http.MethodGet, "/teams/red")
+	ass.Equal(t, 1, len(params), "stale params")
+	ass.Equal(t, "red", params["team"], "wrong team")
+
+	serve(router, http.MethodGet, "/health")
+	ass.Equal(t, 0, len(params), "stale params")
+}
+
+func TestRouter_Params_OutliveTheHandler(t *testing.T) {
+
+	var kept *http.Request
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		if kept == nil {
+			kept = r
+		}
+	})
+
+	serve(router, http.MethodGet, "/users/1")
+	serve(router, http.MethodGet, "/users/2")
+
+	ass.Equal(t, nil, kept.Context().Err(), "unexpected context error")
+	ass.Equal(t, "1", mux.ParamValue(kept, "id"), "kept request sees another request's params")
+}
+
+func TestRouter_Params_RouteShadowsHostAndMount(t *testing.T) {
+
+	var params map[string]string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		params = mux.ParamsFor(r)
+	}
+
+	users := mux.NewRouter()
+	users.Register(http.MethodGet, "/:id", handler)
+
+	router := mux.NewRouter()
+	router.Host(":id.example.com").Mount("/orgs/:org/users", users)
+
+	serve(router, http.MethodGet, "/orgs/baba/users/42", withHost("acme.example.com"))
+
+	ass.Equal(t, 2, len(params), "wrong param count")
+	ass.Equal(t, "42", params["id"], "route did not shadow the host param")
+	ass.Equal(t, "baba", params["org"], "wrong org")
+}
+
+func TestSetParams(t *testing.T) {
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	ass.Equal(t, true, mux.ParamsFor(r) == nil, "expected no params")
+	ass.EmptyString(t, mux.ParamValue(r, "id"), "expected no value")
+
+	r = mux.SetParams(mux.SetParams(r, map[string]string{"id": "1", "org": "baba"}), map[string]string{"id": "2"})
+
+	ass.Equal(t, "2", mux.ParamValue(r, "id"), "wrong id")
+	ass.EmptyString(t, mux.ParamValue(r, "org"), "params were merged")
+}
+
+func BenchmarkRouter_Tree_ParamValues(b *testing.B) {
+
+	router := mux.NewRouter()
+	registerBenchmarkRoutes(router.Register, 100)
+	router.Register(http.MethodGet, "/users/:id/items/:item", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = mux.ParamValue(r, "id"), mux.ParamValue(r, "item")
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/baba/items/flag", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, r)
+	}
+}
+
+func TestRouter_Params_SpillBeyondInlineStorage(t *testing.T) {
+
+	var params map[string]string
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/:a/:b/:c/:d/:e/:f", func(w http.ResponseWriter, r *http.Request) {
+		params = mux.ParamsFor(r)
+	})
+
+	serve(router, http.MethodGet, "/1/2/3/4/5/6")
+
+	ass.Equal(t, 6, len(params), "wrong param count")
+	ass.Equal(t, "5", params["e"], "wrong e")
+	ass.Equal(t, "6", params["f"], "wrong f")
+}
@@ -0,0 +1,207 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PathPolicy decides how a request path that is not in its canonical form is matched.
+type PathPolicy int
+
+const (
+	// PathLenient matches the canonical form of the path and serves it. It is the default.
+	PathLenient PathPolicy = iota
+	// PathStrict matches the path as it is.
+	PathStrict
+	// PathRedirect redirects to the canonical form of the path when it matches a route, with 301
+	// for GET and HEAD requests and 308 otherwise.
+	PathRedirect
+)
+
+var keyMountPrefix = struct{ name string }{"mount prefix"}
+
+// cleanPath applies the duplicate slash and dot segment policies to path and strips its leading
+// slash. It also reports whether a policy asks for a redirect to the result.
+func (r *Router) cleanPath(path string) (string, bool) {
+
+	redirect := false
+
+	if r.DuplicateSlashes != PathStrict && strings.Contains(path, "//") {
+		path = collapseSlashes(path)
+		redirect = r.DuplicateSlashes == PathRedirect
+	}
+
+	if r.DotSegments != PathStrict && hasDotSegments(path) {
+		path = removeDotSegments(path)
+		redirect = redirect || r.DotSegments == PathRedirect
+	}
+
+	return strings.TrimPrefix(path, "/"), redirect
+}
+
+// resolve looks path up in tree, retrying with the trailing slash toggled unless the trailing
+// slash policy is strict. It returns the route, the path it matched and whether it was toggled.
+func (r *Router) resolve(tree *node, path string, sel selector) (*Route, string, bool) {
+
+	if tree == nil {
+		return nil, path, false
+	}
+
+	sel.fold = r.CaseInsensitive
+	if route := tree.find(path, &sel); route != nil {
+		return route, path, false
+	}
+
+	if r.TrailingSlash == PathStrict || path == "" {
+		return nil, path, false
+	}
+
+	toggled := path + "/"
+	if strings.HasSuffix(path, "/") {
+		toggled = path[:len(path)-1]
+	}
+
+	if route := tree.find(toggled, &sel); route != nil {
+		return route, toggled, true
+	}
+
+	return nil, path, false
+}
+
+// redirect sends the client to the escaped path, keeping the prefix the router is mounted under
+// and the query.
+func (r *Router) redirect(w http.ResponseWriter, req *http.Request, path string) {
+
+	prefix, _ := req.Context().Value(keyMountPrefix).(string)
+
+	target := prefix + "/" + path
+	if strings.HasPrefix(target, "//") {
+		target = "/" + strings.TrimLeft(target, "/")
+	}
+
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+
+	code := http.StatusPermanentRedirect
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+
+	w.Header().Set("Location", target)
+	w.WriteHeader(code)
+}
+
+// withPath returns a shallow copy of req with its URL path replaced by the escaped path.
+func withPath(req *http.Request, escaped string) *http.Request {
+
+	clone := new(http.Request)
+	*clone = *req
+
+	clone.URL = new(url.URL)
+	*clone.URL = *req.URL
+
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		path = escaped
+	}
+
+	clone.URL.Path = path
+	clone.URL.RawPath = ""
+	if clone.URL.EscapedPath() != escaped {
+		clone.URL.RawPath = escaped
+	}
+
+	return clone
+}
+
+func withMountPrefix(req *http.Request, prefix string) *http.Request {
+
+	parent, _ := req.Context().Value(keyMountPrefix).(string)
+	return req.WithContext(context.WithValue(req.Context(), keyMountPrefix, parent+prefix))
+}
+
+func collapseSlashes(path string) string {
+
+	var b strings.Builder
+	b.Grow(len(path))
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
+
+var dotUnescaper = strings.NewReplacer("%2e", ".", "%2E", ".")
+
+// dotSegment returns "." or ".." for segments of the escaped path that unescape to them, as in
+// "%2e%2e", and seg itself otherwise.
+func dotSegment(seg string) string {
+
+	if len(seg) > len("%2e%2e") || !strings.Contains(seg, "%") {
+		return seg
+	}
+
+	if unescaped := dotUnescaper.Replace(seg); unescaped == "." || unescaped == ".." {
+		return unescaped
+	}
+
+	return seg
+}
+
+func hasDotSegments(path string) bool {
+
+	for path != "" {
+		var seg string
+		seg, path, _ = strings.Cut(path, "/")
+
+		if seg = dotSegment(seg); seg == "." || seg == ".." {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeDotSegments resolves "." and ".." segments like path.Clean, including their escaped forms,
+// but keeps empty segments and the trailing slash, which are governed by their own policies.
+func removeDotSegments(path string) string {
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	last := dotSegment(segments[len(segments)-1])
+	trailing := last == "" || last == "." || last == ".."
+
+	cleaned := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch dotSegment(seg) {
+		case ".":
+		case "..":
+			if len(cleaned) > 0 {
+				cleaned = cleaned[:len(cleaned)-1]
+			}
+		default:
+			cleaned = append(cleaned, seg)
+		}
+	}
+
+	result := "/" + strings.Join(cleaned, "/")
+	if trailing && !strings.HasSuffix(result, "/") {
+		result += "/"
+	}
+
+	return result
+}
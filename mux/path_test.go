@@ -0,0 +1,306 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+var paramPathHandler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(r.URL.Path + " " + mux.ParamsFor(r)["id"]))
+}
+
+type pathCase struct {
+	name     string
+	method   string
+	path     string
+	code     int
+	body     string
+	location string
+}
+
+func runPathCases(t *testing.T, router *mux.Router, tc []pathCase) {
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			method := c.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			w := serve(router, method, c.path)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			ass.Equal(t, c.location, w.Header().Get("Location"), "wrong location")
+
+			if c.code == http.StatusOK {
+				ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			}
+		})
+	}
+}
+
+func newPathRouter() *mux.Router {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/", paramPathHandler)
+	router.Register(http.MethodGet, "/users/:id", paramPathHandler)
+	router.Register(http.MethodPost, "/users/:id", paramPathHandler)
+	router.Register(http.MethodGet, "/teams/", paramPathHandler)
+
+	return router
+}
+
+func TestRouter_TrailingSlash_Lenient(t *testing.T) {
+
+	runPathCases(t, newPathRouter(), []pathCase{
+		{name: "canonical", path: "/users/5", code: http.StatusOK, body: "/users/5 5"},
+		{name: "added slash", path: "/users/5/", code: http.StatusOK, body: "/users/5 5"},
+		{name: "missing slash", path: "/teams", code: http.StatusOK, body: "/teams/ "},
+		{name: "root", path: "/", code: http.StatusOK, body: "/ "},
+		{name: "unknown", path: "/flag/", code: http.StatusNotFound},
+	})
+}
+
+func TestRouter_TrailingSlash_Strict(t *testing.T) {
+
+	router := newPathRouter()
+	router.TrailingSlash = mux.PathStrict
+	router.Register(http.MethodGet, "/teams", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("no slash"))
+	})
+
+	runPathCases(t, router, []pathCase{
+		{name: "canonical", path: "/users/5", code: http.StatusOK, body: "/users/5 5"},
+		{name: "added slash", path: "/users/5/", code: http.StatusNotFound},
+		{name: "with slash route", path: "/teams/", code: http.StatusOK, body: "/teams/ "},
+		{name: "without slash route", path: "/teams", code: http.StatusOK, body: "no slash"},
+	})
+}
+
+func TestRouter_TrailingSlash_Redirect(t *testing.T) {
+
+	router := newPathRouter()
+	router.TrailingSlash = mux.PathRedirect
+
+	runPathCases(t, router, []pathCase{
+		{name: "canonical", path: "/users/5", code: http.StatusOK, body: "/users/5 5"},
+		{name: "added slash", path: "/users/5/", code: http.StatusMovedPermanently, location: "/users/5"},
+		{name: "missing slash", path: "/teams", code: http.StatusMovedPermanently, location: "/teams/"},
+		{name: "keeps query", path: "/users/5/?baba=you", code: http.StatusMovedPermanently, location: "/users/5?baba=you"},
+		{name: "post", method: http.MethodPost, path: "/users/5/", code: http.StatusPermanentRedirect, location: "/users/5"},
+		{name: "unknown", path: "/flag/", code: http.StatusNotFound},
+	})
+}
+
+func TestRouter_TrailingSlash_RedirectInMountedRouter(t *testing.T) {
+
+	sub := newPathRouter()
+	sub.TrailingSlash = mux.PathRedirect
+
+	router := mux.NewRouter()
+	router.Mount("/api/:version", sub)
+
+	runPathCases(t, router, []pathCase{
+		{name: "keeps prefix", path: "/api/v1/users/5/", code: http.StatusMovedPermanently, location: "/api/v1/users/5"},
+	})
+}
+
+func TestRouter_DuplicateSlashes(t *testing.T) {
+
+	tc := []struct {
+		policy mux.PathPolicy
+		cases  []pathCase
+	}{
+		{mux.PathLenient, []pathCase{
+			{name: "inner", path: "/users//5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "leading", path: "//users/5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "trailing", path: "/users/5//", code: http.StatusOK, body: "/users/5 5"},
+		}},
+		{mux.PathStrict, []pathCase{
+			{name: "inner", path: "/users//5", code: http.StatusNotFound},
+			{name: "leading", path: "//users/5", code: http.StatusNotFound},
+		}},
+		{mux.PathRedirect, []pathCase{
+			{name: "inner", path: "/users//5", code: http.StatusMovedPermanently, location: "/users/5"},
+			{name: "leading", path: "//users/5", code: http.StatusMovedPermanently, location: "/users/5"},
+			{name: "unknown", path: "/flag//5", code: http.StatusNotFound},
+		}},
+	}
+
+	for _, c := range tc {
+		router := newPathRouter()
+		router.DuplicateSlashes = c.policy
+
+		runPathCases(t, router, c.cases)
+	}
+}
+
+func TestRouter_DotSegments(t *testing.T) {
+
+	tc := []struct {
+		policy mux.PathPolicy
+		cases  []pathCase
+	}{
+		{mux.PathLenient, []pathCase{
+			{name: "parent", path: "/users/baba/../5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "current", path: "/users/./5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "above root", path: "/../../users/5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "trailing dot", path: "/teams/.", code: http.StatusOK, body: "/teams/ "},
+			{name: "escaped parent", path: "/users/baba/%2e%2e/5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "mixed case escaped parent", path: "/users/baba/.%2E/5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "escaped current", path: "/users/%2e/5", code: http.StatusOK, body: "/users/5 5"},
+			{name: "escaped trailing dot", path: "/teams/%2E", code: http.StatusOK, body: "/teams/ "},
+			{name: "escaped dots in a name", path: "/users/%2e%2e%2e", code: http.StatusOK, body: "/users/... ..."},
+		}},
+		{mux.PathStrict, []pathCase{
+			{name: "parent", path: "/users/baba/../5", code: http.StatusNotFound},
+			{name: "dot as parameter", path: "/users/..", code: http.StatusOK, body: "/users/.. .."},
+		}},
+		{mux.PathRedirect, []pathCase{
+			{name: "parent", path: "/users/baba/../5", code: http.StatusMovedPermanently, location: "/users/5"},
+			{name: "back to root", path: "/users/..", code: http.StatusMovedPermanently, location: "/"},
+			{name: "escaped parent", path: "/users/baba/%2e%2e/5", code: http.StatusMovedPermanently, location: "/users/5"},
+		}},
+	}
+
+	for _, c := range tc {
+		router := newPathRouter()
+		router.DotSegments = c.policy
+
+		runPathCases(t, router, c.cases)
+	}
+}
+
+func TestRouter_DotSegments_EscapedTraversal(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/files/*rest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path + " " + mux.ParamsFor(r)["rest"]))
+	})
+
+	runPathCases(t, router, []pathCase{
+		{name: "escaped", path: "/files/%2e%2e/%2e%2e/etc/passwd", code: http.StatusNotFound},
+		{name: "upper case", path: "/files/%2E%2E/%2E%2E/etc/passwd", code: http.StatusNotFound},
+		{name: "stays inside", path: "/files/a/%2e%2e/b", code: http.StatusOK, body: "/files/b b"},
+	})
+}
+
+func TestRouter_CaseInsensitive(t *testing.T) {
+
+	router := newPathRouter()
+	runPathCases(t, router, []pathCase{
+		{name: "sensitive", path: "/USERS/Baba", code: http.StatusNotFound},
+	})
+
+	router.CaseInsensitive = true
+	runPathCases(t, router, []pathCase{
+		{name: "insensitive", path: "/USERS/Baba", code: http.StatusOK, body: "/USERS/Baba Baba"},
+	})
+}
+
+func TestRouter_CaseInsensitive_PrefersFirstRegistered(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/Users", paramsHandler("upper"))
+	router.Register(http.MethodGet, "/uSers", paramsHandler("mixed"))
+	router.Register(http.MethodGet, "/users", paramsHandler("lower"))
+	router.CaseInsensitive = true
+
+	for i := 0; i < 20; i++ {
+		ass.Equal(t, "upper", serve(router, http.MethodGet, "/USERS").Body.String(), "wrong handler")
+		ass.Equal(t, "lower", serve(router, http.MethodGet, "/users").Body.String(), "exact match not preferred")
+	}
+}
+
+func TestRouter_CaseInsensitive_Conflicts(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.CaseInsensitive = true
+	router.Conflicts = mux.ErrorOnConflict
+
+	router.Register(http.MethodGet, "/Users/:id", dudHandler)
+	router.Register(http.MethodGet, "/users/:name", dudHandler)
+	router.Register(http.MethodGet, "/users/:id/posts", dudHandler)
+
+	ass.Equal(t, "mux: route GET /users/:name conflicts with registered route GET /Users/:id", router.Err().Error(), "wrong error")
+	ass.Equal(t, 2, len(router.Routes()), "wrong route count")
+}
+
+func TestRouter_Policies_Combined(t *testing.T) {
+
+	router := newPathRouter()
+	router.TrailingSlash = mux.PathRedirect
+	router.DuplicateSlashes = mux.PathRedirect
+	router.DotSegments = mux.PathLenient
+
+	runPathCases(t, router, []pathCase{
+		{name: "all fixed at once", path: "//users/baba/..//5/", code: http.StatusMovedPermanently, location: "/users/5"},
+	})
+}
+
+func TestRouter_EscapedPath(t *testing.T) {
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		params := mux.ParamsFor(r)
+		_, _ = w.Write([]byte(params["key"] + "|" + params["rest"] + "|" + r.URL.EscapedPath()))
+	}
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/files/:key", handler)
+	router.Register(http.MethodGet, "/blobs/*rest", handler)
+	router.Register(http.MethodGet, "/hello world", handler)
+	router.Mount("/mnt", http.HandlerFunc(handler))
+
+	raw := mux.NewRouter()
+	raw.RawParams = true
+	raw.Register(http.MethodGet, "/files/:key", handler)
+	raw.Register(http.MethodGet, "/blobs/*rest", handler)
+
+	tc := []struct {
+		name   string
+		router *mux.Router
+		path   string
+		code   int
+		body   string
+	}{
+		{"encoded slash", router, "/files/a%2Fb", http.StatusOK, "a/b||/files/a%2Fb"},
+		{"encoded space", router, "/files/a%20b", http.StatusOK, "a b||/files/a%20b"},
+		{"catch-all per segment", router, "/blobs/x%2Fy/z%20w", http.StatusOK, "|x/y/z w|/blobs/x%2Fy/z%20w"},
+		{"encoded static", router, "/hello%20world", http.StatusOK, "||/hello%20world"},
+		{"encoded slash is one segment", router, "/files%2Fa", http.StatusNotFound, ""},
+		{"mount keeps escapes", router, "/mnt/a%2Fb/c", http.StatusOK, "||/a%2Fb/c"},
+		{"raw param", raw, "/files/a%2Fb", http.StatusOK, "a%2Fb||/files/a%2Fb"},
+		{"raw catch-all", raw, "/blobs/x%2Fy/z%20w", http.StatusOK, "|x%2Fy/z%20w|/blobs/x%2Fy/z%20w"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(c.router, http.MethodGet, c.path)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			if c.code == http.StatusOK {
+				ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			}
+		})
+	}
+}
+
+func TestRouter_EscapedPath_Redirect(t *testing.T) {
+
+	router := newPathRouter()
+	router.TrailingSlash = mux.PathRedirect
+
+	runPathCases(t, router, []pathCase{
+		{name: "keeps escapes", path: "/users/a%2Fb/?q=1", code: http.StatusMovedPermanently, location: "/users/a%2Fb?q=1"},
+	})
+}
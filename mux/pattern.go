@@ -0,0 +1,239 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type (
+	segmentKind int
+
+	// segment is a parsed path token: static text, a ":name" parameter with an optional
+	// "<constraint>" or a trailing "*name" catch-all. Trailing parameters followed by "?" are
+	// optional and may name their default value after "?=".
+	segment struct {
+		kind       segmentKind
+		value      string
+		constraint *constraint
+		optional   bool
+		fallback   string
+	}
+
+	// constraint restricts the values a parameter matches. It is either one of the named built-in
+	// constraints or a regular expression matched against the whole segment.
+	constraint struct {
+		source string
+		match  func(value string) bool
+	}
+)
+
+const (
+	segmentStatic segmentKind = iota
+	segmentParam
+	segmentCatchAll
+)
+
+var builtinConstraints = map[string]func(value string) bool{
+	"int":   isInt,
+	"uint":  isUint,
+	"alpha": isAlpha,
+	"alnum": isAlnum,
+	"uuid":  isUUID,
+}
+
+func parsePattern(path string) ([]segment, error) {
+
+	trimmed := strings.TrimLeft(path, "/")
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	tokens := strings.Split(trimmed, "/")
+	segments := make([]segment, len(tokens))
+
+	for i, token := range tokens {
+		if token == "" && i != len(tokens)-1 {
+			return nil, fmt.Errorf("empty segment in path %q", path)
+		}
+
+		seg, err := parseSegment(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w in path %q", err, path)
+		}
+
+		if seg.kind == segmentCatchAll && i != len(tokens)-1 {
+			return nil, fmt.Errorf("catch-all must be the last segment in path %q", path)
+		}
+
+		if i > 0 && segments[i-1].optional && !seg.optional {
+			return nil, fmt.Errorf("optional segments must be trailing in path %q", path)
+		}
+
+		segments[i] = seg
+	}
+
+	return segments, nil
+}
+
+func parseSegment(token string) (segment, error) {
+
+	switch {
+	case strings.HasPrefix(token, "*"):
+		if len(token) == 1 {
+			return segment{}, errors.New("unnamed catch-all")
+		}
+
+		return segment{kind: segmentCatchAll, value: token[1:]}, nil
+	case !strings.HasPrefix(token, ":"):
+		return segment{kind: segmentStatic, value: token}, nil
+	}
+
+	name, source, constrained := strings.Cut(token[1:], "<")
+
+	var suffix string
+	if constrained {
+		end := strings.LastIndexByte(source, '>')
+		if end < 1 {
+			return segment{}, fmt.Errorf("malformed constraint on parameter %q", name)
+		}
+
+		source, suffix = source[:end], source[end+1:]
+	} else if i := strings.IndexByte(name, '?'); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	}
+
+	if name == "" {
+		return segment{}, errors.New("unnamed parameter")
+	}
+
+	seg := segment{kind: segmentParam, value: name}
+	if suffix != "" {
+		fallback, ok := strings.CutPrefix(suffix, "?")
+		if !ok || fallback != "" && !strings.HasPrefix(fallback, "=") {
+			return segment{}, fmt.Errorf("malformed optional parameter %q", name)
+		}
+
+		seg.optional = true
+		seg.fallback = strings.TrimPrefix(fallback, "=")
+	}
+
+	if !constrained {
+		return seg, nil
+	}
+
+	c, err := parseConstraint(source)
+	if err != nil {
+		return segment{}, fmt.Errorf("invalid constraint on parameter %q: %v", name, err)
+	}
+
+	if seg.fallback != "" && !c.match(seg.fallback) {
+		return segment{}, fmt.Errorf("default %q violates the constraint on parameter %q", seg.fallback, name)
+	}
+
+	seg.constraint = c
+	return seg, nil
+}
+
+// variants returns the segments of each path the segments match: all of them and, for every
+// trailing optional parameter, those before it.
+func variants(segments []segment) [][]segment {
+
+	result := [][]segment{segments}
+	for i := len(segments) - 1; i >= 0 && segments[i].optional; i-- {
+		result = append(result, segments[:i])
+	}
+
+	return result
+}
+
+func parseConstraint(source string) (*constraint, error) {
+
+	if match, ok := builtinConstraints[source]; ok {
+		return &constraint{source: source, match: match}, nil
+	}
+
+	expr, err := regexp.Compile("^(?:" + source + ")$")
+	if err != nil {
+		return nil, err
+	}
+
+	return &constraint{source: source, match: expr.MatchString}, nil
+}
+
+func isInt(value string) bool {
+
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	return isUint(value)
+}
+
+func isUint(value string) bool {
+	return value != "" && allBytes(value, isDigit)
+}
+
+func isAlpha(value string) bool {
+	return value != "" && allBytes(value, isLetter)
+}
+
+func isAlnum(value string) bool {
+
+	return value != "" && allBytes(value, func(c byte) bool {
+		return isLetter(c) || isDigit(c)
+	})
+}
+
+func isUUID(value string) bool {
+
+	if len(value) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if value[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(value[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func allBytes(value string, valid func(c byte) bool) bool {
+
+	for i := 0; i < len(value); i++ {
+		if !valid(value[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,111 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_Constraints(t *testing.T) {
+
+	tc := []struct {
+		name    string
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"int", "/users/:id<int>", "/users/42", true},
+		{"negative int", "/users/:id<int>", "/users/-42", true},
+		{"int rejects text", "/users/:id<int>", "/users/baba", false},
+		{"uint rejects sign", "/users/:id<uint>", "/users/-42", false},
+		{"alpha", "/users/:name<alpha>", "/users/baba", true},
+		{"alpha rejects digits", "/users/:name<alpha>", "/users/baba1", false},
+		{"alnum", "/users/:name<alnum>", "/users/baba1", true},
+		{"uuid", "/users/:id<uuid>", "/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301", true},
+		{"uuid rejects short", "/users/:id<uuid>", "/users/3f2504e0-4f89-11d3-9a0c", false},
+		{"regex", "/posts/:slug<[a-z-]+>", "/posts/baba-is-you", true},
+		{"regex is anchored", "/posts/:slug<[a-z-]+>", "/posts/Baba-is-you", false},
+		{"regex alternation is anchored", "/posts/:kind<draft|final>", "/posts/finalized", false},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Register(http.MethodGet, c.pattern, targetHandler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+
+			router.ServeHTTP(w, r)
+
+			if c.match {
+				ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+				return
+			}
+
+			ass.Equal(t, http.StatusNotFound, w.Code, "wrong status code")
+		})
+	}
+}
+
+func TestRouter_Constraints_Dispatch(t *testing.T) {
+
+	router := mux.NewRouter()
+
+	matched := ""
+	params := map[string]string{}
+	for _, pattern := range []string{"/users/:name", "/users/:id<int>", "/users/:id<uuid>/posts", "/users/:name/posts"} {
+		pattern := pattern
+		router.Register(http.MethodGet, pattern, func(w http.ResponseWriter, r *http.Request) {
+			matched = pattern
+			params = mux.ParamsFor(r)
+		})
+	}
+
+	tc := []struct {
+		path    string
+		pattern string
+		param   string
+		value   string
+	}{
+		{"/users/42", "/users/:id<int>", "id", "42"},
+		{"/users/baba", "/users/:name", "name", "baba"},
+		{"/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301/posts", "/users/:id<uuid>/posts", "id", "3f2504e0-4f89-11d3-9a0c-0305e82c3301"},
+		{"/users/baba/posts", "/users/:name/posts", "name", "baba"},
+		{"/users/42/posts", "/users/:name/posts", "name", "42"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+
+			router.ServeHTTP(w, r)
+
+			ass.Equal(t, c.pattern, matched, "wrong route")
+			ass.Equal(t, c.value, params[c.param], "wrong parameter value")
+		})
+	}
+}
+
+func TestRouter_InvalidConstraint_Panics(t *testing.T) {
+
+	for _, pattern := range []string{"/users/:id<[a-z>", "/users/:id<int", "/users/:id<>"} {
+		router := mux.NewRouter()
+		action := func() {
+			router.Register(http.MethodGet, pattern, dudHandler)
+		}
+
+		ass.Panics(t, action, "failed to panic on invalid constraint")
+	}
+}
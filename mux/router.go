@@ -8,43 +8,82 @@
 package mux
 
 import (
-	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-lean/fun/middle"
 )
 
 type (
+	// Router dispatches requests to the routes registered on it. Routes, mounts, hosts and names
+	// can be added while it serves requests, each change publishing a new immutable route table.
+	// The exported fields configure the router and are not safe to change while it serves.
 	Router struct {
-		getPaths     []Route
-		postPaths    []Route
-		putPaths     []Route
-		patchPaths   []Route
-		deletePaths  []Route
-		tracePaths   []Route
-		headPaths    []Route
-		optionsPaths []Route
-
-		NotFoundHandler http.HandlerFunc
+		mu     sync.Mutex
+		table  atomic.Pointer[table]
+		parent *Router
+		chain  *middle.Chain
+
+		// NotFoundHandler, MethodNotAllowedHandler and UnsupportedMediaTypeHandler default to plain
+		// text responses. While left at their defaults or nil, those of a router mounted on or
+		// created by another one fall back to the handlers of that router.
+		NotFoundHandler             http.HandlerFunc
+		MethodNotAllowedHandler     http.HandlerFunc
+		UnsupportedMediaTypeHandler http.HandlerFunc
+
+		// AutoHead serves HEAD requests without a HEAD route from the matching GET route.
+		AutoHead bool
+		// AutoOptions answers OPTIONS requests without an OPTIONS route with the allowed methods.
+		AutoOptions bool
+
+		// TrailingSlash, DuplicateSlashes and DotSegments decide how request paths with a toggled
+		// trailing slash, repeated slashes or "." and ".." segments are matched.
+		TrailingSlash    PathPolicy
+		DuplicateSlashes PathPolicy
+		DotSegments      PathPolicy
+		// CaseInsensitive matches static segments regardless of case.
+		CaseInsensitive bool
+		// RawParams keeps parameter values percent-encoded instead of unescaping them per segment.
+		RawParams bool
+
+		// Conflicts decides how routes that duplicate or shadow a registered one are reported.
+		Conflicts ConflictPolicy
+		// OnConflict receives conflicts under WarnOnConflict once the registration that caused them
+		// is done, so it may use the router. It defaults to log.Print.
+		OnConflict func(err *ConflictError)
+
+		errs     []error
+		warnings []*ConflictError
 	}
 
 	Route struct {
-		tokens    []string
-		tokensLen int
-		handler   routeHandler
+		router      *Router
+		method      string
+		pattern     string
+		handlerName string
+		segments    []segment
+		matchers    []Matcher
+		handler     routeHandler
+		// pathValues also exposes the parameters through Request.PathValue.
+		pathValues bool
+		// skipped marks a route left out of the table as a conflict.
+		skipped bool
 	}
 
 	routeHandler struct {
 		handler http.HandlerFunc
-		params  map[int]string
+		params  int
 	}
 )
 
-const errUnknownMethodFmt = "unknown method: %q"
+const errInvalidMethodFmt = "invalid method: %q"
 
 var (
-	keyRouteParams = struct{}{}
-
 	_notFoundHandlerDefault = func(w http.ResponseWriter, _ *http.Request) {
 
 		w.WriteHeader(http.StatusNotFound)
@@ -52,195 +91,347 @@ var (
 
 		_, _ = w.Write([]byte(payload))
 	}
+
+	_methodNotAllowedHandlerDefault = func(w http.ResponseWriter, _ *http.Request) {
+
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		payload := http.StatusText(http.StatusMethodNotAllowed)
+
+		_, _ = w.Write([]byte(payload))
+	}
+
+	_unsupportedMediaTypeHandlerDefault = func(w http.ResponseWriter, _ *http.Request) {
+
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		payload := http.StatusText(http.StatusUnsupportedMediaType)
+
+		_, _ = w.Write([]byte(payload))
+	}
+
+	_notImplementedHandler = func(w http.ResponseWriter, _ *http.Request) {
+
+		w.WriteHeader(http.StatusNotImplemented)
+		payload := http.StatusText(http.StatusNotImplemented)
+
+		_, _ = w.Write([]byte(payload))
+	}
+
+	standardMethods = map[string]bool{
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodPost:    true,
+		http.MethodPut:     true,
+		http.MethodPatch:   true,
+		http.MethodDelete:  true,
+		http.MethodConnect: true,
+		http.MethodOptions: true,
+		http.MethodTrace:   true,
+	}
 )
 
 func NewRouter() *Router {
 
 	router := &Router{
-		NotFoundHandler: _notFoundHandlerDefault,
+		chain:                       middle.New(),
+		NotFoundHandler:             _notFoundHandlerDefault,
+		MethodNotAllowedHandler:     _methodNotAllowedHandlerDefault,
+		UnsupportedMediaTypeHandler: _unsupportedMediaTypeHandlerDefault,
+		AutoHead:                    true,
+		AutoOptions:                 true,
 	}
 
+	router.table.Store(newTable())
 	return router
 }
 
-func (r *Router) Register(method, path string, handler http.HandlerFunc) {
+// Register adds a route for method and path. Path segments starting with ":" are parameters and
+// may be constrained with a built-in (int, uint, alpha, alnum, uuid) or a regular expression, as in
+// ":id<int>" or ":slug<[a-z-]+>". Trailing parameters marked with "?" are optional and take the
+// default given after "?=" when absent, as in "/reports/:year/:month<int>?=1". The route is then
+// also registered for the path without them, so it conflicts with routes registered for either
+// path, while static segments keep their precedence. A trailing "*name" segment captures the rest
+// of the path. Routes registered for the method "*" serve any method without a route of its own.
+// Matchers restrict the route to requests with certain headers, query parameters or content types.
+func (r *Router) Register(method, path string, handler http.HandlerFunc, matchers ...Matcher) *Route {
+
+	route := r.newRoute(method, path, handler, matchers...)
+
+	r.registerRoute(method, route)
+	return route
+}
+
+func (r *Router) newRoute(method, path string, handler http.HandlerFunc, matchers ...Matcher) *Route {
 
-	params := make(map[int]string)
-	route := Route{
+	segments, err := parsePattern(path)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return r.buildRoute(method, "/"+strings.TrimPrefix(path, "/"), segments, handler, matchers...)
+}
+
+func (r *Router) buildRoute(method, pattern string, segments []segment, handler http.HandlerFunc, matchers ...Matcher) *Route {
+
+	route := &Route{
+		router:      r,
+		method:      method,
+		pattern:     pattern,
+		handlerName: handlerName(handler),
+		segments:    segments,
+		matchers:    matchers,
 		handler: routeHandler{
 			handler: handler,
-			params:  params,
 		},
 	}
 
-	if path == "/" {
-		route.tokens = []string{"/"}
-		route.tokensLen = 1
-		r.registerRoute(method, route)
+	for _, seg := range segments {
+		if seg.kind != segmentStatic {
+			route.handler.params++
+		}
+	}
 
+	return route
+}
+
+// ServeHTTP passes requests for a registered host to its router and otherwise matches the escaped
+// request path, so encoded slashes stay within their segment.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	t := r.table.Load()
+	if len(t.hosts) > 0 && t.serveHost(w, req) {
 		return
 	}
 
-	path = strings.Trim(path, "/")
-	route.tokens = strings.Split(path, "/")
-	route.tokensLen = len(route.tokens)
+	escaped := req.URL.EscapedPath()
+	path, redirect := r.cleanPath(escaped)
 
-	for i, token := range route.tokens {
-		if !strings.HasPrefix(token, ":") {
-			continue
+	route, path, toggled := r.resolve(t.trees[req.Method], path, selector{req: req})
+	if route == nil && req.Method == http.MethodHead && r.AutoHead {
+		route, path, toggled = r.resolve(t.trees[http.MethodGet], path, selector{req: req})
+		if route != nil {
+			headWriter := &headResponseWriter{ResponseWriter: w}
+			defer headWriter.finish()
+
+			w = headWriter
 		}
+	}
 
-		params[i] = token[1:]
+	if route == nil {
+		route, path, toggled = r.resolve(t.trees[methodAny], path, selector{req: req})
 	}
 
-	r.registerRoute(method, route)
-}
+	if route == nil {
+		route, path, toggled = r.resolve(t.mounts, path, selector{req: req})
+	}
 
-func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if route == nil && r.chain.Len() > 0 {
+		r.serveMissWithSteps(w, req, t, path)
+		return
+	}
 
-	var tokens []string
-	if req.URL.Path == "/" {
-		tokens = []string{"/"}
-	} else {
-		path := strings.Trim(req.URL.Path, "/")
-		tokens = strings.Split(path, "/")
+	if route == nil {
+		r.serveMiss(w, req, t, path)
+		return
 	}
 
-	routeHandler, ok := r.matchHandler(req.Method, tokens)
-	if !ok {
-		r.NotFoundHandler(w, req)
+	if redirect || toggled && r.TrailingSlash == PathRedirect {
+		r.redirect(w, req, path)
 		return
 	}
 
-	if len(routeHandler.params) > 0 {
-		req = r.loadParams(req, tokens, routeHandler.params)
+	if len(escaped) != len(path)+1 || escaped[1:] != path {
+		req = withPath(req, "/"+path)
 	}
 
-	routeHandler.handler(w, req)
-}
+	if route.handler.params == 0 {
+		route.handler.handler(w, req)
+		return
+	}
+
+	req, ctx := withParams(req)
+	r.appendParams(&ctx.params, path, route.segments)
 
-func SetParams(r *http.Request, vars map[string]string) *http.Request {
+	if route.pathValues {
+		for _, entry := range ctx.params.entries {
+			req.SetPathValue(entry.name, entry.value)
+		}
+	}
 
-	paramsContext := context.WithValue(r.Context(), keyRouteParams, vars)
-	return r.WithContext(paramsContext)
+	route.handler.handler(w, req)
 }
 
-func ParamsFor(r *http.Request) map[string]string {
+// serveMiss answers requests without a route for their method: 415 when a route only rejects
+// their content type, 204 with an Allow header for automatic OPTIONS, 405 with an Allow header when
+// the path is routed under other methods, 501 for methods that are neither standard nor registered
+// and 404 otherwise.
+func (r *Router) serveMiss(w http.ResponseWriter, req *http.Request, t *table, path string) {
 
-	params := r.Context().Value(keyRouteParams)
-	if params == nil {
-		return make(map[string]string)
+	if route, _, _ := r.resolve(t.trees[req.Method], path, selector{req: req, anyType: true}); route != nil {
+		r.unsupportedMediaType(w, req)
+		return
 	}
 
-	return params.(map[string]string)
+	autoOptions := req.Method == http.MethodOptions && r.AutoOptions
+
+	var allowed []string
+	if autoOptions && req.URL.Path == "*" {
+		for _, method := range t.methods {
+			if method != methodAny {
+				allowed = append(allowed, method)
+			}
+		}
+
+		allowed = r.withAutoMethods(allowed)
+	} else if autoOptions {
+		allowed = r.allowedMethods(t, path, selector{})
+	} else {
+		allowed = r.allowedMethods(t, path, selector{req: req, anyType: true})
+	}
+
+	if len(allowed) == 0 {
+		if _, ok := t.trees[req.Method]; !ok && !standardMethods[req.Method] {
+			_notImplementedHandler(w, req)
+			return
+		}
+
+		r.notFound(w, req)
+		return
+	}
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if autoOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	r.methodNotAllowed(w, req)
 }
 
-func (r *Router) registerRoute(method string, route Route) {
-
-	switch method {
-	case http.MethodGet:
-		r.getPaths = append(r.getPaths, route)
-	case http.MethodPost:
-		r.postPaths = append(r.postPaths, route)
-	case http.MethodPatch:
-		r.patchPaths = append(r.patchPaths, route)
-	case http.MethodPut:
-		r.putPaths = append(r.putPaths, route)
-	case http.MethodDelete:
-		r.deletePaths = append(r.deletePaths, route)
-	case http.MethodHead:
-		r.headPaths = append(r.headPaths, route)
-	case http.MethodTrace:
-		r.tracePaths = append(r.tracePaths, route)
-	case http.MethodOptions:
-		r.optionsPaths = append(r.optionsPaths, route)
+func (r *Router) notFound(w http.ResponseWriter, req *http.Request) {
+
+	switch {
+	case r.parent != nil && isDefaultHandler(r.NotFoundHandler, _notFoundHandlerDefault):
+		r.parent.notFound(w, req)
+	case r.NotFoundHandler != nil:
+		r.NotFoundHandler(w, req)
 	default:
-		panic(fmt.Sprintf(errUnknownMethodFmt, method))
+		_notFoundHandlerDefault(w, req)
 	}
 }
 
-func (r *Router) loadParams(req *http.Request, tokens []string, params map[int]string) *http.Request {
+func (r *Router) methodNotAllowed(w http.ResponseWriter, req *http.Request) {
 
-	vars := make(map[string]string)
-	for k, v := range params {
-		vars[v] = tokens[k]
+	switch {
+	case r.parent != nil && isDefaultHandler(r.MethodNotAllowedHandler, _methodNotAllowedHandlerDefault):
+		r.parent.methodNotAllowed(w, req)
+	case r.MethodNotAllowedHandler != nil:
+		r.MethodNotAllowedHandler(w, req)
+	default:
+		_methodNotAllowedHandlerDefault(w, req)
 	}
-
-	return SetParams(req, vars)
 }
 
-func (r *Router) matchHandler(method string, tokens []string) (*routeHandler, bool) {
+func (r *Router) unsupportedMediaType(w http.ResponseWriter, req *http.Request) {
 
-	paths := r.getPathsFor(method)
-	maxMatchCount := 0
+	switch {
+	case r.parent != nil && isDefaultHandler(r.UnsupportedMediaTypeHandler, _unsupportedMediaTypeHandlerDefault):
+		r.parent.unsupportedMediaType(w, req)
+	case r.UnsupportedMediaTypeHandler != nil:
+		r.UnsupportedMediaTypeHandler(w, req)
+	default:
+		_unsupportedMediaTypeHandlerDefault(w, req)
+	}
+}
 
-	var handler *routeHandler
+// isDefaultHandler reports whether handler is nil or the default it is compared to.
+func isDefaultHandler(handler, fallback http.HandlerFunc) bool {
+	return handler == nil || reflect.ValueOf(handler).Pointer() == reflect.ValueOf(fallback).Pointer()
+}
 
-	for i := 0; i < len(paths); i++ {
-		path := paths[i]
+// allowedMethods returns the methods with a route for path accepting the request of sel. Automatic
+// OPTIONS answers pass no request, as preflight requests do not carry the headers routes match on.
+func (r *Router) allowedMethods(t *table, path string, sel selector) []string {
 
-		if path.tokensLen != len(tokens) {
+	var allowed []string
+	for _, method := range t.methods {
+		if method == methodAny {
 			continue
 		}
 
-		matchScore := evaluateHandler(path.tokens, tokens)
-
-		if matchScore == path.tokensLen {
-			return &path.handler, true
+		if route, _, _ := r.resolve(t.trees[method], path, sel); route != nil {
+			allowed = append(allowed, method)
 		}
+	}
+
+	return r.withAutoMethods(allowed)
+}
+
+// withAutoMethods adds the automatically served HEAD and OPTIONS methods to allowed and sorts it.
+func (r *Router) withAutoMethods(allowed []string) []string {
+
+	if len(allowed) == 0 {
+		return nil
+	}
 
-		if matchScore > maxMatchCount {
-			maxMatchCount = matchScore
-			handler = &path.handler
+	has := func(method string) bool {
+		for _, m := range allowed {
+			if m == method {
+				return true
+			}
 		}
+
+		return false
+	}
+
+	result := append([]string(nil), allowed...)
+	if r.AutoHead && has(http.MethodGet) && !has(http.MethodHead) {
+		result = append(result, http.MethodHead)
 	}
 
-	if handler == nil {
-		return nil, false
+	if r.AutoOptions && !has(http.MethodOptions) {
+		result = append(result, http.MethodOptions)
 	}
 
-	return handler, true
+	sort.Strings(result)
+	return result
 }
 
-func evaluateHandler(pathTokens, requestTokens []string) int {
+func (r *Router) registerRoute(method string, route *Route) {
 
-	matchScore := 0
-	for i, token := range pathTokens {
-		if token == requestTokens[i] {
-			matchScore++
-			continue
-		}
+	if !isToken(method) {
+		panic(fmt.Sprintf(errInvalidMethodFmt, method))
+	}
 
-		if strings.HasPrefix(token, ":") {
-			continue
+	r.update(func(t *table) {
+		tree, ok := t.trees[method]
+		if !ok {
+			tree = newNode()
+
+			t.methods = append(t.methods, method)
+			sort.Strings(t.methods)
 		}
 
-		return 0
+		t.trees[method] = r.insert(t, tree, route)
+	})
+}
+
+// isToken reports whether method is a valid RFC 9110 token.
+func isToken(method string) bool {
+
+	if method == "" {
+		return false
 	}
 
-	return matchScore
-}
+	for i := 0; i < len(method); i++ {
+		c := method[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
 
-func (r *Router) getPathsFor(method string) []Route {
-
-	switch method {
-	case http.MethodGet:
-		return r.getPaths
-	case http.MethodPost:
-		return r.postPaths
-	case http.MethodPatch:
-		return r.patchPaths
-	case http.MethodPut:
-		return r.putPaths
-	case http.MethodDelete:
-		return r.deletePaths
-	case http.MethodHead:
-		return r.headPaths
-	case http.MethodTrace:
-		return r.tracePaths
-	case http.MethodOptions:
-		return r.optionsPaths
-	}
-
-	panic(fmt.Sprintf(errUnknownMethodFmt, method))
+	return true
 }
@@ -8,8 +8,10 @@
 package mux_test
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-lean/fun/ass"
@@ -231,25 +233,60 @@ func TestRouter_Match(t *testing.T) {
 
 func TestHandle_InvalidMethod_Panics(t *testing.T) {
 
-	router := mux.NewRouter()
-	action := func() {
-		router.Register("baba", "/", func(w http.ResponseWriter, r *http.Request) {})
+	for _, method := range []string{"", "ba ba", "baba\n"} {
+		router := mux.NewRouter()
+		action := func() {
+			router.Register(method, "/", func(w http.ResponseWriter, r *http.Request) {})
+		}
+
+		ass.Panics(t, action, "failed to panic on invalid method handle")
 	}
+}
+
+func TestRouter_CustomMethods(t *testing.T) {
+
+	for _, method := range []string{http.MethodConnect, "PROPFIND", "MKCOL", "baba"} {
+		t.Run(method, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Register(method, "/files/:name", targetHandler)
 
-	ass.Panics(t, action, "failed to panic on invalid menthod handle")
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(method, "/files/baba", nil)
+
+			router.ServeHTTP(w, r)
+
+			ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+			ass.Equal(t, "baba", w.Body.String())
+		})
+	}
 }
 
-func TestRouter_ServeHTTP_InvalidMethod_Panics(t *testing.T) {
+func TestRouter_ServeHTTP_UnknownMethod(t *testing.T) {
 
 	router := mux.NewRouter()
-	action := func() {
-		w := httptest.NewRecorder()
-		r := httptest.NewRequest("baba", "/", nil)
+	router.Register(http.MethodGet, "/baba", dudHandler)
 
-		router.ServeHTTP(w, r)
+	tc := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+	}{
+		{"routed path - method not allowed", "PROPFIND", "/baba", http.StatusMethodNotAllowed},
+		{"unknown path - not implemented", "PROPFIND", "/flag", http.StatusNotImplemented},
+		{"standard method - not found", http.MethodPost, "/flag", http.StatusNotFound},
 	}
 
-	ass.Panics(t, action, "failed to panic on invalid method serve")
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(c.method, c.path, nil)
+
+			router.ServeHTTP(w, r)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+		})
+	}
 }
 
 func TestRouter_ServeHTTP_RouteParams(t *testing.T) {
@@ -355,3 +392,398 @@ func TestRouter_RootWithQueryParams(t *testing.T) {
 	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
 	ass.Equal(t, "baba", w.Body.String(), "wrong response payload")
 }
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodPut, "/users/:id", dudHandler)
+	router.Register(http.MethodGet, "/users/:id", dudHandler)
+	router.Register(http.MethodPost, "/users", dudHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodDelete, "/users/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusMethodNotAllowed, w.Code, "wrong status code")
+	ass.Equal(t, "GET, HEAD, OPTIONS, PUT", w.Header().Get("Allow"), "wrong allow header")
+	ass.Equal(t, http.StatusText(http.StatusMethodNotAllowed), w.Body.String(), "wrong body")
+}
+
+func TestRouter_MethodNotAllowed_CustomHandler(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/baba", dudHandler)
+	router.MethodNotAllowedHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
+	ass.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get("Allow"), "wrong allow header")
+}
+
+func TestRouter_AutoHead(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Baba", mux.ParamsFor(r)["id"])
+		w.WriteHeader(http.StatusAccepted)
+
+		_, _ = w.Write([]byte("baba"))
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/users/is-you", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusAccepted, w.Code, "wrong status code")
+	ass.Equal(t, "is-you", w.Header().Get("X-Baba"), "wrong header")
+	ass.Equal(t, "4", w.Header().Get("Content-Length"), "wrong content length")
+	ass.EmptyString(t, w.Body.String(), "unexpected body")
+}
+
+func TestRouter_AutoHead_ExplicitRouteWins(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/baba", dudHandler)
+	router.Register(http.MethodHead, "/baba", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusTeapot, w.Code, "wrong status code")
+}
+
+func TestRouter_AutoHead_Disabled(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.AutoHead = false
+	router.Register(http.MethodGet, "/baba", dudHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusMethodNotAllowed, w.Code, "wrong status code")
+	ass.Equal(t, "GET, OPTIONS", w.Header().Get("Allow"), "wrong allow header")
+}
+
+func TestRouter_AutoOptions(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", dudHandler)
+	router.Register(http.MethodDelete, "/users/:id", dudHandler)
+	router.Register(http.MethodPost, "/users", dudHandler)
+
+	tc := []struct {
+		name    string
+		path    string
+		code    int
+		allowed string
+	}{
+		{"path", "/users/baba", http.StatusNoContent, "DELETE, GET, HEAD, OPTIONS"},
+		{"asterisk", "*", http.StatusNoContent, "DELETE, GET, HEAD, OPTIONS, POST"},
+		{"unknown path", "/flag", http.StatusNotFound, ""},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodOptions, "/", nil)
+			r.URL.Path = c.path
+
+			router.ServeHTTP(w, r)
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			ass.Equal(t, c.allowed, w.Header().Get("Allow"), "wrong allow header")
+		})
+	}
+}
+
+func TestRouter_AutoOptions_ExplicitRouteWins(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/baba", dudHandler)
+	router.Register(http.MethodOptions, "/baba", targetHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodOptions, "/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "baba", w.Body.String(), "wrong body")
+}
+
+func TestRouter_AutoOptions_Disabled(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.AutoOptions = false
+	router.Register(http.MethodGet, "/baba", dudHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodOptions, "/baba", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusMethodNotAllowed, w.Code, "wrong status code")
+	ass.Equal(t, "GET, HEAD", w.Header().Get("Allow"), "wrong allow header")
+}
+
+func TestRouter_UnknownPath_NotFound(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/baba", dudHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/flag", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusNotFound, w.Code, "wrong status code")
+	ass.EmptyString(t, w.Header().Get("Allow"), "unexpected allow header")
+}
+
+func TestRouter_CatchAll(t *testing.T) {
+
+	tc := []struct {
+		name   string
+		routes []string
+		path   string
+		route  string
+		param  string
+		value  string
+	}{
+		{"captures remainder", []string{"/static/*filepath"}, "/static/css/app.css", "/static/*filepath", "filepath", "css/app.css"},
+		{"captures single segment", []string{"/static/*filepath"}, "/static/app.css", "/static/*filepath", "filepath", "app.css"},
+		{"captures empty remainder", []string{"/api/v1/*rest"}, "/api/v1", "/api/v1/*rest", "rest", ""},
+		{"root catch-all", []string{"/*rest"}, "/baba/is/you", "/*rest", "rest", "baba/is/you"},
+		{"static wins", []string{"/files/*path", "/files/readme"}, "/files/readme", "/files/readme", "", ""},
+		{"param wins", []string{"/files/*path", "/files/:name"}, "/files/baba", "/files/:name", "name", "baba"},
+		{"backtracks to catch-all", []string{"/files/*path", "/files/:name/meta"}, "/files/baba/data", "/files/*path", "path", "baba/data"},
+		{"deeper static wins", []string{"/files/*path", "/files/baba/:name"}, "/files/baba/is", "/files/baba/:name", "name", "is"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			matched := ""
+			params := map[string]string{}
+			for _, path := range c.routes {
+				path := path
+				router.Register(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+					matched = path
+					params = mux.ParamsFor(r)
+				})
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, c.path, nil)
+
+			router.ServeHTTP(w, r)
+
+			ass.Equal(t, c.route, matched, "wrong route")
+			ass.Equal(t, c.value, params[c.param], "wrong parameter value")
+		})
+	}
+}
+
+func TestRouter_CatchAll_NotLast_Panics(t *testing.T) {
+
+	router := mux.NewRouter()
+	action := func() {
+		router.Register(http.MethodGet, "/static/*filepath/baba", dudHandler)
+	}
+
+	ass.Panics(t, action, "failed to panic on catch-all before the last segment")
+}
+
+func TestRouter_UnnamedParam_Panics(t *testing.T) {
+
+	for _, path := range []string{"/static/*", "/users/:"} {
+		router := mux.NewRouter()
+		action := func() {
+			router.Register(http.MethodGet, path, dudHandler)
+		}
+
+		ass.Panics(t, action, "failed to panic on unnamed parameter")
+	}
+}
+
+func TestRouter_StaticBeforeParam_Backtracks(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/me/settings", dudHandler)
+	router.Register(http.MethodGet, "/users/:id/profile", targetHandler)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/me/profile", nil)
+
+	router.ServeHTTP(w, r)
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "baba", w.Body.String(), "wrong handler")
+}
+
+func TestRouter_StaticLookup_DoesNotAllocate(t *testing.T) {
+
+	router := mux.NewRouter()
+	registerBenchmarkRoutes(router.Register, 100)
+	router.Register(http.MethodGet, "/users/list/all", func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/list/all", nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		router.ServeHTTP(w, r)
+	})
+
+	ass.Equal(t, 0.0, allocs, "lookup allocated")
+}
+
+// scanRouter is the linear route scanner the tree replaced. It is kept as a benchmark baseline.
+type scanRouter struct {
+	routes []scanRoute
+}
+
+type scanRoute struct {
+	tokens  []string
+	params  map[int]string
+	handler http.HandlerFunc
+}
+
+func (s *scanRouter) Register(_, path string, handler http.HandlerFunc, _ ...mux.Matcher) *mux.Route {
+
+	route := scanRoute{
+		tokens:  strings.Split(strings.Trim(path, "/"), "/"),
+		params:  make(map[int]string),
+		handler: handler,
+	}
+
+	for i, token := range route.tokens {
+		if strings.HasPrefix(token, ":") {
+			route.params[i] = token[1:]
+		}
+	}
+
+	s.routes = append(s.routes, route)
+	return nil
+}
+
+func (s *scanRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	tokens := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	var match *scanRoute
+	maxScore := 0
+
+	for i := range s.routes {
+		route := &s.routes[i]
+		if len(route.tokens) != len(tokens) {
+			continue
+		}
+
+		score := 0
+		for j, token := range route.tokens {
+			if token == tokens[j] {
+				score++
+				continue
+			}
+
+			if !strings.HasPrefix(token, ":") {
+				score = -1
+				break
+			}
+		}
+
+		if score == len(route.tokens) {
+			match = route
+			break
+		}
+
+		if score > maxScore {
+			maxScore = score
+			match = route
+		}
+	}
+
+	if match == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if len(match.params) > 0 {
+		vars := make(map[string]string)
+		for k, v := range match.params {
+			vars[v] = tokens[k]
+		}
+
+		req = mux.SetParams(req, vars)
+	}
+
+	match.handler(w, req)
+}
+
+func registerBenchmarkRoutes(register func(method, path string, handler http.HandlerFunc, matchers ...mux.Matcher) *mux.Route, count int) {
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	for i := 0; i < count; i++ {
+		register(http.MethodGet, fmt.Sprintf("/api/v1/resource%d", i), handler)
+		register(http.MethodGet, fmt.Sprintf("/api/v1/resource%d/:id", i), handler)
+		register(http.MethodGet, fmt.Sprintf("/api/v1/resource%d/:id/items/:item", i), handler)
+	}
+}
+
+func benchmarkRouter(b *testing.B, router interface {
+	http.Handler
+	Register(method, path string, handler http.HandlerFunc, matchers ...mux.Matcher) *mux.Route
+}, path string) {
+
+	registerBenchmarkRoutes(router.Register, 100)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(w, r)
+	}
+}
+
+func BenchmarkRouter_Tree_Static(b *testing.B) {
+	benchmarkRouter(b, mux.NewRouter(), "/api/v1/resource99")
+}
+
+func BenchmarkRouter_Scan_Static(b *testing.B) {
+	benchmarkRouter(b, &scanRouter{}, "/api/v1/resource99")
+}
+
+func BenchmarkRouter_Tree_Params(b *testing.B) {
+	benchmarkRouter(b, mux.NewRouter(), "/api/v1/resource99/baba/items/flag")
+}
+
+func BenchmarkRouter_Scan_Params(b *testing.B) {
+	benchmarkRouter(b, &scanRouter{}, "/api/v1/resource99/baba/items/flag")
+}
+
+func BenchmarkRouter_Tree_NotFound(b *testing.B) {
+	benchmarkRouter(b, mux.NewRouter(), "/api/v2/resource99")
+}
+
+func BenchmarkRouter_Scan_NotFound(b *testing.B) {
+	benchmarkRouter(b, &scanRouter{}, "/api/v2/resource99")
+}
@@ -0,0 +1,148 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-lean/fun/middle"
+)
+
+// RouteInfo describes a registered route. Mounts are listed with the method "*" and routes of
+// host routers with the host pattern they were registered under.
+type RouteInfo struct {
+	Host        string   `json:"host,omitempty"`
+	Method      string   `json:"method"`
+	Pattern     string   `json:"pattern"`
+	Params      []string `json:"params,omitempty"`
+	Matchers    []string `json:"matchers,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	HandlerName string   `json:"handler"`
+}
+
+// Routes returns the routes and mounts of r in registration order, followed by those of its host
+// routers in the order hosts are matched.
+func (r *Router) Routes() []RouteInfo {
+
+	r.mu.Lock()
+	t := r.table.Load()
+	r.mu.Unlock()
+
+	names := make(map[*Route]string, len(t.names))
+	for name, route := range t.names {
+		names[route] = name
+	}
+
+	routes := make([]RouteInfo, 0, len(t.routes))
+	for _, route := range t.routes {
+		routes = append(routes, route.info(names[route]))
+	}
+
+	for _, host := range t.hosts {
+		for _, route := range host.router.Routes() {
+			if route.Host == "" {
+				route.Host = host.pattern
+			}
+
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// WriteRoutes writes routes to w as an aligned table sorted by host, pattern and method. Patterns
+// of host routes are prefixed with their host.
+func WriteRoutes(w io.Writer, routes []RouteInfo) error {
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "METHOD\tPATTERN\tNAME\tHANDLER")
+
+	for _, route := range sortedRoutes(routes) {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", route.Method, route.Host+route.Pattern, route.Name, route.HandlerName)
+	}
+
+	return tw.Flush()
+}
+
+// WriteRoutesJSON writes routes to w as a JSON array sorted by host, pattern and method.
+func WriteRoutesJSON(w io.Writer, routes []RouteInfo) error {
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(sortedRoutes(routes))
+}
+
+func (rt *Route) info(name string) RouteInfo {
+
+	var params []string
+	for _, seg := range rt.segments {
+		if seg.kind != segmentStatic && seg.value != "" {
+			params = append(params, seg.value)
+		}
+	}
+
+	var matchers []string
+	for _, m := range rt.matchers {
+		matchers = append(matchers, m.String())
+	}
+
+	return RouteInfo{
+		Method:      rt.method,
+		Pattern:     rt.pattern,
+		Params:      params,
+		Matchers:    matchers,
+		Name:        name,
+		HandlerName: rt.handlerName,
+	}
+}
+
+func sortedRoutes(routes []RouteInfo) []RouteInfo {
+
+	sorted := make([]RouteInfo, len(routes))
+	copy(sorted, routes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Host != sorted[j].Host {
+			return sorted[i].Host < sorted[j].Host
+		}
+
+		if sorted[i].Pattern != sorted[j].Pattern {
+			return sorted[i].Pattern < sorted[j].Pattern
+		}
+
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	return sorted
+}
+
+// handlerName identifies handler by its function name or, for other handlers, by its type.
+func handlerName(handler any) string {
+
+	switch h := handler.(type) {
+	case http.HandlerFunc, func(http.ResponseWriter, *http.Request), middle.Handler:
+		if reflect.ValueOf(h).IsNil() {
+			return ""
+		}
+
+		name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+		return strings.TrimSuffix(name, "-fm")
+	}
+
+	return fmt.Sprintf("%T", handler)
+}
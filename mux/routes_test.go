@@ -0,0 +1,127 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+	"github.com/go-lean/fun/resp"
+)
+
+func listUsers(w http.ResponseWriter, r *http.Request) {}
+
+func getOrder(r *http.Request) resp.Result {
+	return resp.New(http.StatusOK, nil, "")
+}
+
+func newListedRouter() *mux.Router {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodPost, "/users", listUsers)
+	router.Register(http.MethodGet, "/users/:id<int>/posts/:slug", listUsers).Name("post")
+	router.Group("/api").Handle(http.MethodGet, "/orders/:id", getOrder).Name("order")
+	router.Register(http.MethodGet, "/users", listUsers).Name("users")
+	router.Mount("/static", http.FileServer(http.Dir(".")))
+
+	return router
+}
+
+func TestRouter_Routes(t *testing.T) {
+
+	routes := newListedRouter().Routes()
+
+	ass.Equal(t, 5, len(routes), "wrong route count")
+
+	post := routes[1]
+	ass.Equal(t, http.MethodGet, post.Method, "wrong method")
+	ass.Equal(t, "/users/:id<int>/posts/:slug", post.Pattern, "wrong pattern")
+	ass.Equal(t, "post", post.Name, "wrong name")
+	ass.Equal(t, 2, len(post.Params), "wrong params count")
+	ass.Equal(t, "id", post.Params[0], "wrong first param")
+	ass.Equal(t, "slug", post.Params[1], "wrong second param")
+	ass.Equal(t, "github.com/go-lean/fun/mux_test.listUsers", post.HandlerName, "wrong handler")
+
+	order := routes[2]
+	ass.Equal(t, "/api/orders/:id", order.Pattern, "wrong group pattern")
+	ass.Equal(t, "github.com/go-lean/fun/mux_test.getOrder", order.HandlerName, "wrong group handler")
+
+	mount := routes[4]
+	ass.Equal(t, "*", mount.Method, "wrong mount method")
+	ass.Equal(t, "/static", mount.Pattern, "wrong mount pattern")
+	ass.Equal(t, "*http.fileHandler", mount.HandlerName, "wrong mount handler")
+}
+
+func TestWriteRoutes_Table(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	err := mux.WriteRoutes(buf, newListedRouter().Routes())
+
+	expected := strings.Join([]string{
+		"METHOD  PATTERN                      NAME   HANDLER",
+		"GET     /api/orders/:id              order  github.com/go-lean/fun/mux_test.getOrder",
+		"*       /static                             *http.fileHandler",
+		"GET     /users                       users  github.com/go-lean/fun/mux_test.listUsers",
+		"POST    /users                              github.com/go-lean/fun/mux_test.listUsers",
+		"GET     /users/:id<int>/posts/:slug  post   github.com/go-lean/fun/mux_test.listUsers",
+		"",
+	}, "\n")
+
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, expected, buf.String(), "wrong table")
+}
+
+func TestWriteRoutes_JSON(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	err := mux.WriteRoutesJSON(buf, newListedRouter().Routes())
+
+	var routes []mux.RouteInfo
+	_ = json.Unmarshal(buf.Bytes(), &routes)
+
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, 5, len(routes), "wrong route count")
+	ass.Equal(t, "/api/orders/:id", routes[0].Pattern, "routes not sorted")
+	ass.Equal(t, "id", routes[0].Params[0], "wrong params")
+}
+
+func TestRouter_Routes_IncludeHosts(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users", listUsers)
+	router.Host(":tenant.example.com").Register(http.MethodGet, "/users", listUsers).Name("tenant")
+	router.Host("api.example.com").Register(http.MethodPost, "/orders", listUsers)
+
+	routes := router.Routes()
+
+	ass.Equal(t, 3, len(routes), "wrong route count")
+	ass.EmptyString(t, routes[0].Host, "unexpected host")
+	ass.Equal(t, "api.example.com", routes[1].Host, "wrong host")
+	ass.Equal(t, "/orders", routes[1].Pattern, "wrong host pattern")
+	ass.Equal(t, ":tenant.example.com", routes[2].Host, "wrong parameter host")
+	ass.Equal(t, "tenant", routes[2].Name, "wrong host route name")
+
+	buf := &bytes.Buffer{}
+	err := mux.WriteRoutes(buf, routes)
+
+	expected := strings.Join([]string{
+		"METHOD  PATTERN                    NAME    HANDLER",
+		"GET     /users                             github.com/go-lean/fun/mux_test.listUsers",
+		"GET     :tenant.example.com/users  tenant  github.com/go-lean/fun/mux_test.listUsers",
+		"POST    api.example.com/orders             github.com/go-lean/fun/mux_test.listUsers",
+		"",
+	}, "\n")
+
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, expected, buf.String(), "wrong table")
+}
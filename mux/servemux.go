@@ -0,0 +1,142 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// HandleFunc registers handler for a pattern in the syntax of http.ServeMux, as in
+// "GET /items/{id}", "/files/{path...}" or "api.example.com/{$}". Patterns without a method serve
+// any method and patterns with a host are registered on the router returned by Host. A "{name}"
+// wildcard matches a segment, a final "{name...}" wildcard the rest of the path, and a pattern
+// ending in a slash matches every path under it unless it ends in "{$}". Wildcard values are
+// visible through both ParamsFor and Request.PathValue. Unlike http.ServeMux, static segments take
+// precedence over wildcards regardless of registration order, as for Register, and a pattern ending
+// in a slash also serves the path without it, as in "/static" for "/static/", where http.ServeMux
+// redirects to the path with the slash.
+func (r *Router) HandleFunc(pattern string, handler http.HandlerFunc) *Route {
+
+	method, host, path, err := splitServeMuxPattern(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	segments, err := parseServeMuxPath(path)
+	if err != nil {
+		panic(fmt.Sprintf("%v in pattern %q", err, pattern))
+	}
+
+	target := r
+	if host != "" {
+		target = r.Host(host)
+	}
+
+	route := target.buildRoute(method, path, segments, handler)
+	route.pathValues = true
+
+	target.registerRoute(method, route)
+	return route
+}
+
+// splitServeMuxPattern splits a pattern into its method, "*" when there is none, host and path.
+func splitServeMuxPattern(pattern string) (method, host, path string, err error) {
+
+	method = methodAny
+	rest := pattern
+
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		method, rest = pattern[:i], strings.TrimLeft(pattern[i:], " \t")
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i < 0 {
+		return "", "", "", fmt.Errorf("missing path in pattern %q", pattern)
+	}
+
+	return method, rest[:i], rest[i:], nil
+}
+
+// parseServeMuxPath translates a http.ServeMux pattern path into segments. A trailing slash
+// becomes an unnamed catch-all, while "{$}" keeps it as an empty last segment.
+func parseServeMuxPath(path string) ([]segment, error) {
+
+	tokens := strings.Split(path[1:], "/")
+	segments := make([]segment, 0, len(tokens))
+
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+
+		switch {
+		case token == "" && last:
+			return append(segments, segment{kind: segmentCatchAll}), nil
+		case token == "":
+			return nil, errors.New("empty segment")
+		case token == "{$}" && !last:
+			return nil, errors.New("{$} not at the end")
+		case token == "{$}" && i == 0:
+			return segments, nil
+		case token == "{$}":
+			return append(segments, segment{kind: segmentStatic}), nil
+		case !strings.HasPrefix(token, "{"):
+			if strings.ContainsAny(token, "{}") {
+				return nil, fmt.Errorf("wildcard not a whole segment in %q", token)
+			}
+
+			segments = append(segments, segment{kind: segmentStatic, value: token})
+			continue
+		}
+
+		if !strings.HasSuffix(token, "}") {
+			return nil, fmt.Errorf("wildcard not a whole segment in %q", token)
+		}
+
+		name, rest := strings.CutSuffix(token[1:len(token)-1], "...")
+		if !isIdentifier(name) {
+			return nil, fmt.Errorf("bad wildcard name %q", name)
+		}
+
+		for _, seg := range segments {
+			if seg.kind != segmentStatic && seg.value == name {
+				return nil, fmt.Errorf("duplicate wildcard name %q", name)
+			}
+		}
+
+		if !rest {
+			segments = append(segments, segment{kind: segmentParam, value: name})
+			continue
+		}
+
+		if !last {
+			return nil, fmt.Errorf("%q wildcard not at the end", name)
+		}
+
+		segments = append(segments, segment{kind: segmentCatchAll, value: name})
+	}
+
+	return segments, nil
+}
+
+func isIdentifier(name string) bool {
+
+	if name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_HandleFunc(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.HandleFunc("GET /items/{id}", paramsHandler("item"))
+	router.HandleFunc("DELETE\t/items/{id}", paramsHandler("delete"))
+	router.HandleFunc("GET /items/{$}", paramsHandler("items"))
+	router.HandleFunc("/files/{path...}", paramsHandler("file"))
+	router.HandleFunc("GET /static/", paramsHandler("static"))
+	router.HandleFunc("GET /{$}", paramsHandler("home"))
+	router.HandleFunc("GET api.example.com/items/{id}", paramsHandler("api"))
+
+	tc := []struct {
+		name   string
+		method string
+		host   string
+		path   string
+		code   int
+		body   string
+	}{
+		{"wildcard", http.MethodGet, "", "/items/5", http.StatusOK, "item id=5"},
+		{"other method", http.MethodDelete, "", "/items/5", http.StatusOK, "delete id=5"},
+		{"auto head", http.MethodHead, "", "/items/5", http.StatusOK, ""},
+		{"not allowed", http.MethodPost, "", "/items/5", http.StatusMethodNotAllowed, ""},
+		{"exact trailing slash", http.MethodGet, "", "/items/", http.StatusOK, "items"},
+		{"rest wildcard", http.MethodPut, "", "/files/a/b%20c", http.StatusOK, "file path=a/b c"},
+		{"empty rest wildcard", http.MethodGet, "", "/files/", http.StatusOK, "file path="},
+		{"trailing slash prefix", http.MethodGet, "", "/static/css/app.css", http.StatusOK, "static"},
+		{"trailing slash prefix without slash", http.MethodGet, "", "/static", http.StatusOK, "static"},
+		{"exact root", http.MethodGet, "", "/", http.StatusOK, "home"},
+		{"root is exact", http.MethodGet, "", "/missing", http.StatusNotFound, ""},
+		{"host", http.MethodGet, "api.example.com", "/items/7", http.StatusOK, "api id=7"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(router, c.method, c.path, withHost(c.host))
+
+			ass.Equal(t, c.code, w.Code, "wrong status code")
+			if c.code == http.StatusOK {
+				ass.Equal(t, c.body, w.Body.String(), "wrong body")
+			}
+		})
+	}
+}
+
+func TestRouter_HandleFunc_URL(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.HandleFunc("GET /items/{id}", dudHandler).Name("item")
+	router.HandleFunc("GET /files/{path...}", dudHandler).Name("file")
+	router.HandleFunc("GET /static/", dudHandler).Name("static")
+
+	tc := []struct {
+		route  string
+		params []string
+		url    string
+	}{
+		{"item", []string{"id", "a b"}, "/items/a%20b"},
+		{"file", []string{"path", "css/app.css"}, "/files/css/app.css"},
+		{"static", nil, "/static/"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.route, func(t *testing.T) {
+			url, err := router.URL(c.route, c.params...)
+
+			ass.Equal(t, nil, err, "unexpected error")
+			ass.Equal(t, c.url, url, "wrong url")
+		})
+	}
+}
+
+func TestRouter_HandleFunc_InvalidPattern_Panics(t *testing.T) {
+
+	patterns := []string{
+		"GET items",
+		"GET /items/{id",
+		"GET /items/a{id}",
+		"GET /items/{}",
+		"GET /items/{1d}",
+		"GET /items/{id}/{id}",
+		"GET /files/{path...}/edit",
+		"GET /items/{$}/edit",
+		"GET /items//edit",
+		"G(T /items",
+	}
+
+	for _, pattern := range patterns {
+		action := func() {
+			mux.NewRouter().HandleFunc(pattern, dudHandler)
+		}
+
+		ass.Panics(t, action, "failed to panic on invalid pattern", pattern)
+	}
+}
+
+func TestRouter_HandleFunc_MatchesServeMux(t *testing.T) {
+
+	patterns := []string{"GET /items/{id}", "GET /items/{$}", "/files/{path...}", "GET /static/"}
+
+	handler := func(pattern string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(pattern + " " + r.PathValue("id") + " " + r.PathValue("path")))
+		}
+	}
+
+	router := mux.NewRouter()
+	std := http.NewServeMux()
+	for _, pattern := range patterns {
+		router.HandleFunc(pattern, handler(pattern))
+		std.HandleFunc(pattern, handler(pattern))
+	}
+
+	paths := []string{"/items/5", "/items/", "/files/a/b", "/static/css/app.css", "/other"}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			got := serve(router, http.MethodGet, path)
+			want := serve(std, http.MethodGet, path)
+
+			ass.Equal(t, want.Code, got.Code, "wrong status code")
+			if want.Code == http.StatusOK {
+				ass.Equal(t, want.Body.String(), got.Body.String(), "wrong body")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import "maps"
+
+// table is an immutable snapshot of the routes of a router. Changes are applied to a copy, which
+// shares every tree node off the changed paths, and published atomically, so requests are served
+// from a consistent table without locking.
+type table struct {
+	trees   map[string]*node
+	methods []string
+	mounts  *node
+	hosts   []*hostRoute
+	names   map[string]*Route
+	routes  []*Route
+}
+
+func newTable() *table {
+
+	return &table{
+		trees:  make(map[string]*node),
+		mounts: newNode(),
+		names:  make(map[string]*Route),
+	}
+}
+
+// clone returns a copy of t that can be changed without affecting t. The slices are capped so
+// appending to them copies.
+func (t *table) clone() *table {
+
+	return &table{
+		trees:   maps.Clone(t.trees),
+		methods: t.methods[:len(t.methods):len(t.methods)],
+		mounts:  t.mounts,
+		hosts:   t.hosts[:len(t.hosts):len(t.hosts)],
+		names:   maps.Clone(t.names),
+		routes:  t.routes[:len(t.routes):len(t.routes)],
+	}
+}
+
+// update applies change to a copy of the route table of r and publishes it. Writers are
+// serialized and a change that panics is not published. Conflict warnings raised by change are
+// passed on once r is unlocked, so OnConflict may use r.
+func (r *Router) update(change func(t *table)) {
+
+	for _, err := range r.apply(change) {
+		r.warn(err)
+	}
+}
+
+func (r *Router) apply(change func(t *table)) []*ConflictError {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.warnings = nil
+
+	next := r.table.Load().clone()
+	change(next)
+
+	r.table.Store(next)
+	return r.warnings
+}
+
+// Replace atomically swaps the routes, mounts, hosts and route names of r for those of other, so a
+// route table can be built up front and put in service in one step. Requests in flight finish on
+// the table they started with. Routers mounted on other or returned by its Host method keep
+// falling back to the handlers of other.
+func (r *Router) Replace(other *Router) {
+
+	other.mu.Lock()
+	next := other.table.Load()
+	errs := other.errs
+	other.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.table.Store(next)
+	r.errs = append([]error(nil), errs...)
+}
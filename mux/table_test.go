@@ -0,0 +1,150 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_ConcurrentRegisterAndServe(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/ping", paramsHandler("pong"))
+
+	const writers, routes = 4, 50
+
+	var writing, reading sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		writing.Add(1)
+		go func(i int) {
+			defer writing.Done()
+
+			for j := 0; j < routes; j++ {
+				path := fmt.Sprintf("/tenants/t%d/items%d/:id", i, j)
+				router.Register(http.MethodGet, path, paramsHandler(path)).Name(path)
+				router.Register(http.MethodPost, path, paramsHandler(path), mux.ContentType("application/json"))
+			}
+
+			router.Host(fmt.Sprintf("t%d.example.com", i)).Register(http.MethodGet, "/", paramsHandler("host"))
+			router.Mount(fmt.Sprintf("/static%d", i), paramsHandler("static"))
+		}(i)
+	}
+
+	done := make(chan struct{})
+	for i := 0; i < writers; i++ {
+		reading.Add(1)
+		go func() {
+			defer reading.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				w := serve(router, http.MethodGet, "/ping")
+				ass.Equal(t, "pong", w.Body.String(), "wrong body")
+
+				serve(router, http.MethodGet, "/tenants/t1/items7/5")
+				serve(router, http.MethodDelete, "/tenants/t2/items3/5")
+				serve(router, http.MethodGet, "/", withHost("t3.example.com"))
+				_, _ = router.URL("/tenants/t0/items1/:id", "id", "1")
+				_ = router.Routes()
+			}
+		}()
+	}
+
+	writing.Wait()
+	close(done)
+	reading.Wait()
+
+	ass.Equal(t, 1+writers*(2*routes+2), len(router.Routes()), "wrong route count")
+
+	w := serve(router, http.MethodGet, "/tenants/t3/items49/5")
+	ass.Equal(t, "/tenants/t3/items49/:id id=5", w.Body.String(), "wrong body")
+}
+
+func TestRouter_ConcurrentRenameAndList(t *testing.T) {
+
+	router := mux.NewRouter()
+	route := router.Register(http.MethodGet, "/users/:id", dudHandler)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 1000; i++ {
+			route.Name(fmt.Sprintf("user%d", i))
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 1000; i++ {
+			_ = router.Routes()
+		}
+	}()
+
+	wg.Wait()
+
+	routes := router.Routes()
+	ass.Equal(t, "user999", routes[0].Name, "wrong name")
+
+	_, err := router.URL("user998", "id", "1")
+	ass.True(t, err != nil, "previous name still resolves")
+}
+
+func TestRouter_Replace(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/old", paramsHandler("old")).Name("old")
+
+	next := mux.NewRouter()
+	next.Register(http.MethodGet, "/new", paramsHandler("new")).Name("new")
+	next.Host("api.example.com").Register(http.MethodGet, "/", paramsHandler("api"))
+
+	router.Replace(next)
+
+	ass.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/old").Code, "old route still served")
+	ass.Equal(t, "new", serve(router, http.MethodGet, "/new").Body.String(), "wrong body")
+	ass.Equal(t, "api", serve(router, http.MethodGet, "/", withHost("api.example.com")).Body.String(), "wrong host body")
+
+	url, err := router.URL("new")
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, "/new", url, "wrong url")
+
+	_, err = router.URL("old")
+	ass.Equal(t, false, err == nil, "expected an error for the replaced route")
+
+	next.Register(http.MethodGet, "/later", paramsHandler("later"))
+	ass.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/later").Code, "later route leaked into the replaced table")
+}
+
+func TestRouter_PanickingRegister_KeepsTable(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id", paramsHandler("user"))
+
+	action := func() {
+		router.Register(http.MethodGet, "/users/:name", paramsHandler("shadowed"))
+	}
+
+	ass.Panics(t, action, "failed to panic on conflict")
+	ass.Equal(t, 1, len(router.Routes()), "wrong route count")
+	ass.Equal(t, "user id=5", serve(router, http.MethodGet, "/users/5").Body.String(), "wrong body")
+}
@@ -0,0 +1,294 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"maps"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// node is a segment of the route tree. Static children take precedence over parameter children,
+// constrained parameters over unconstrained ones and parameters over the catch-all child. Lookups
+// backtrack when a branch does not lead to a route.
+type node struct {
+	static map[string]*node
+	// folded indexes the static children by their lowercase value, keeping the first registered
+	// one, for case-insensitive lookups.
+	folded     map[string]*node
+	params     []*node
+	catchAll   *node
+	constraint *constraint
+	routes     []*Route
+}
+
+func newNode() *node {
+	return &node{}
+}
+
+// insertAt returns a copy of n with route added under segments, sharing every node off their path
+// with n, which is left unchanged. Routes that differ only in parameter names end on the same node,
+// where they are kept ordered by their number of matchers. When a route with the same matchers
+// already ends on the node, it is returned along with n.
+func (n *node) insertAt(route *Route, segments []segment) (*node, *Route) {
+
+	if len(segments) == 0 {
+		return n.withRoute(route)
+	}
+
+	clone := *n
+
+	seg := segments[0]
+	switch seg.kind {
+	case segmentCatchAll:
+		child := n.catchAll
+		if child == nil {
+			child = newNode()
+		}
+
+		next, existing := child.insertAt(route, segments[1:])
+		if existing != nil {
+			return n, existing
+		}
+
+		clone.catchAll = next
+	case segmentParam:
+		i := n.paramIndex(seg.constraint)
+
+		var child *node
+		if i < len(n.params) && sameConstraint(n.params[i].constraint, seg.constraint) {
+			child = n.params[i]
+			clone.params = append([]*node(nil), n.params...)
+		} else {
+			child = &node{constraint: seg.constraint}
+			clone.params = make([]*node, 0, len(n.params)+1)
+			clone.params = append(append(append(clone.params, n.params[:i]...), child), n.params[i:]...)
+		}
+
+		next, existing := child.insertAt(route, segments[1:])
+		if existing != nil {
+			return n, existing
+		}
+
+		clone.params[i] = next
+	default:
+		child, ok := n.static[seg.value]
+		if !ok {
+			child = newNode()
+		}
+
+		next, existing := child.insertAt(route, segments[1:])
+		if existing != nil {
+			return n, existing
+		}
+
+		clone.static = maps.Clone(n.static)
+		if clone.static == nil {
+			clone.static = make(map[string]*node)
+		}
+
+		clone.static[seg.value] = next
+
+		lower := strings.ToLower(seg.value)
+		if folded, ok := n.folded[lower]; !ok || folded == child {
+			clone.folded = maps.Clone(n.folded)
+			if clone.folded == nil {
+				clone.folded = make(map[string]*node)
+			}
+
+			clone.folded[lower] = next
+		}
+	}
+
+	return &clone, nil
+}
+
+// shadowing returns a route ending where a route with the same matchers under segments would,
+// comparing static segments regardless of case, or nil.
+func (n *node) shadowing(route *Route, segments []segment) *Route {
+
+	if len(segments) == 0 {
+		key := route.matcherKey()
+		for _, existing := range n.routes {
+			if existing.matcherKey() == key {
+				return existing
+			}
+		}
+
+		return nil
+	}
+
+	seg, rest := segments[0], segments[1:]
+	switch seg.kind {
+	case segmentCatchAll:
+		if n.catchAll != nil {
+			return n.catchAll.shadowing(route, rest)
+		}
+	case segmentParam:
+		for _, child := range n.params {
+			if sameConstraint(child.constraint, seg.constraint) {
+				return child.shadowing(route, rest)
+			}
+		}
+	default:
+		for value, child := range n.static {
+			if strings.ToLower(value) != strings.ToLower(seg.value) {
+				continue
+			}
+
+			if existing := child.shadowing(route, rest); existing != nil {
+				return existing
+			}
+		}
+	}
+
+	return nil
+}
+
+// withRoute returns a copy of n ending route, or n and the route with the same matchers.
+func (n *node) withRoute(route *Route) (*node, *Route) {
+
+	key := route.matcherKey()
+	for _, existing := range n.routes {
+		if existing.matcherKey() == key {
+			return n, existing
+		}
+	}
+
+	i := sort.Search(len(n.routes), func(i int) bool {
+		return len(n.routes[i].matchers) < len(route.matchers)
+	})
+
+	clone := *n
+	clone.routes = make([]*Route, 0, len(n.routes)+1)
+	clone.routes = append(append(append(clone.routes, n.routes[:i]...), route), n.routes[i:]...)
+
+	return &clone, nil
+}
+
+// paramIndex returns the index of the parameter child for c or, when there is none, the index it
+// is inserted at to keep constrained children ahead of the unconstrained one.
+func (n *node) paramIndex(c *constraint) int {
+
+	for i, child := range n.params {
+		if sameConstraint(child.constraint, c) || child.constraint == nil {
+			return i
+		}
+	}
+
+	return len(n.params)
+}
+
+func sameConstraint(a, b *constraint) bool {
+	return a == nil && b == nil || a != nil && b != nil && a.source == b.source
+}
+
+// find matches path, a slash separated list of escaped segments without the leading slash, against
+// the tree. The empty path is the root and a trailing slash is an empty last segment. Segments are
+// unescaped before they are compared, so only percent-encoded paths allocate. Routes whose matchers
+// reject the request of sel are skipped, and with sel.fold static segments match regardless of case.
+func (n *node) find(path string, sel *selector) *Route {
+
+	if path == "" {
+		return n.end(sel)
+	}
+
+	return n.lookup(path, sel)
+}
+
+func (n *node) lookup(path string, sel *selector) *Route {
+
+	segment, rest, more := strings.Cut(path, "/")
+
+	segment, valid := unescapeSegment(segment)
+	if !valid {
+		return nil
+	}
+
+	child, ok := n.static[segment]
+	if !ok && sel.fold {
+		child, ok = n.staticFold(segment)
+	}
+
+	if ok {
+		if route := child.match(rest, more, sel); route != nil {
+			return route
+		}
+	}
+
+	if segment != "" {
+		for _, child := range n.params {
+			if child.constraint != nil && !child.constraint.match(segment) {
+				continue
+			}
+
+			if route := child.match(rest, more, sel); route != nil {
+				return route
+			}
+		}
+	}
+
+	if n.catchAll != nil {
+		return n.catchAll.pick(sel)
+	}
+
+	return nil
+}
+
+func (n *node) match(rest string, more bool, sel *selector) *Route {
+
+	if !more {
+		return n.end(sel)
+	}
+
+	return n.lookup(rest, sel)
+}
+
+func (n *node) staticFold(segment string) (*node, bool) {
+
+	child, ok := n.folded[strings.ToLower(segment)]
+	return child, ok
+}
+
+// end returns the route of n or, as catch-alls also match an empty remainder, of its catch-all.
+func (n *node) end(sel *selector) *Route {
+
+	if route := n.pick(sel); route != nil || n.catchAll == nil {
+		return route
+	}
+
+	return n.catchAll.pick(sel)
+}
+
+// pick returns the first route of n accepting the request of sel.
+func (n *node) pick(sel *selector) *Route {
+
+	for _, route := range n.routes {
+		if route.accepts(sel) {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// unescapeSegment percent-decodes a path segment, returning it unchanged when it has no escapes.
+func unescapeSegment(segment string) (string, bool) {
+
+	if strings.IndexByte(segment, '%') < 0 {
+		return segment, true
+	}
+
+	value, err := url.PathUnescape(segment)
+	if err != nil {
+		return segment, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,127 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const errDuplicateNameFmt = "duplicate route name: %q"
+
+var (
+	ErrUnknownRoute = errors.New("mux: unknown route")
+	ErrParams       = errors.New("mux: invalid route parameters")
+)
+
+// Name makes the route addressable by Router.URL. Names are unique per router. Naming a route
+// skipped as a conflict has no effect.
+func (rt *Route) Name(name string) *Route {
+
+	rt.router.update(func(t *table) {
+		if rt.skipped {
+			return
+		}
+
+		if _, ok := t.names[name]; ok {
+			panic(fmt.Sprintf(errDuplicateNameFmt, name))
+		}
+
+		for existing, route := range t.names {
+			if route == rt {
+				delete(t.names, existing)
+			}
+		}
+
+		t.names[name] = rt
+	})
+
+	return rt
+}
+
+// URL builds the path of the route called name from params, given as name and value pairs.
+// Values are path escaped, catch-all values segment by segment. Missing, unknown or duplicate
+// params and values violating a constraint are reported as ErrParams.
+func (r *Router) URL(name string, params ...string) (string, error) {
+
+	route, ok := r.table.Load().names[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownRoute, name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: odd number of arguments for route %q", ErrParams, name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		if _, ok := values[params[i]]; ok {
+			return "", fmt.Errorf("%w: duplicate parameter %q for route %q", ErrParams, params[i], name)
+		}
+
+		values[params[i]] = params[i+1]
+	}
+
+	var b strings.Builder
+	for _, seg := range route.segments {
+		if _, ok := values[seg.value]; seg.optional && !ok {
+			break
+		}
+
+		b.WriteByte('/')
+
+		if seg.kind == segmentCatchAll && seg.value == "" {
+			continue
+		}
+
+		if seg.kind == segmentStatic {
+			b.WriteString(url.PathEscape(seg.value))
+			continue
+		}
+
+		value, ok := values[seg.value]
+		if !ok {
+			return "", fmt.Errorf("%w: missing parameter %q for route %q", ErrParams, seg.value, name)
+		}
+
+		delete(values, seg.value)
+
+		if seg.kind == segmentCatchAll {
+			b.WriteString(escapeSegments(value))
+			continue
+		}
+
+		if value == "" || seg.constraint != nil && !seg.constraint.match(value) {
+			return "", fmt.Errorf("%w: invalid value %q for parameter %q of route %q", ErrParams, value, seg.value, name)
+		}
+
+		b.WriteString(url.PathEscape(value))
+	}
+
+	for key := range values {
+		return "", fmt.Errorf("%w: unknown parameter %q for route %q", ErrParams, key, name)
+	}
+
+	if b.Len() == 0 {
+		return "/", nil
+	}
+
+	return b.String(), nil
+}
+
+func escapeSegments(value string) string {
+
+	parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,91 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/mux"
+)
+
+func TestRouter_URL(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/", dudHandler).Name("root")
+	router.Register(http.MethodGet, "/users/:id<int>/posts/:slug", dudHandler).Name("post")
+	router.Register(http.MethodGet, "/static/*filepath", dudHandler).Name("static")
+	router.Register(http.MethodGet, "/hello world/:id", dudHandler).Name("hello")
+	router.Group("/api/v1").Handle(http.MethodGet, "/orders/:id", textHandler("order")).Name("order")
+
+	tc := []struct {
+		name   string
+		route  string
+		params []string
+		url    string
+	}{
+		{"root", "root", nil, "/"},
+		{"params", "post", []string{"slug", "baba-is-you", "id", "42"}, "/users/42/posts/baba-is-you"},
+		{"escaped", "post", []string{"id", "42", "slug", "baba is/you?"}, "/users/42/posts/baba%20is%2Fyou%3F"},
+		{"catch-all", "static", []string{"filepath", "css/app one.css"}, "/static/css/app%20one.css"},
+		{"group", "order", []string{"id", "baba"}, "/api/v1/orders/baba"},
+		{"escaped static", "hello", []string{"id", "1"}, "/hello%20world/1"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			url, err := router.URL(c.route, c.params...)
+
+			ass.Equal(t, nil, err, "unexpected error")
+			ass.Equal(t, c.url, url, "wrong url")
+		})
+	}
+}
+
+func TestRouter_URL_Errors(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/users/:id<int>", dudHandler).Name("user")
+
+	tc := []struct {
+		name   string
+		route  string
+		params []string
+		err    error
+	}{
+		{"unknown route", "baba", nil, mux.ErrUnknownRoute},
+		{"missing param", "user", nil, mux.ErrParams},
+		{"extra param", "user", []string{"id", "42", "name", "baba"}, mux.ErrParams},
+		{"duplicate param", "user", []string{"id", "42", "id", "43"}, mux.ErrParams},
+		{"odd params", "user", []string{"id"}, mux.ErrParams},
+		{"constraint violated", "user", []string{"id", "baba"}, mux.ErrParams},
+		{"empty value", "user", []string{"id", ""}, mux.ErrParams},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := router.URL(c.route, c.params...)
+
+			ass.True(t, errors.Is(err, c.err), "wrong error", err)
+		})
+	}
+}
+
+func TestRoute_Name_Duplicate_Panics(t *testing.T) {
+
+	router := mux.NewRouter()
+	router.Register(http.MethodGet, "/baba", dudHandler).Name("baba")
+
+	action := func() {
+		router.Register(http.MethodPost, "/baba", dudHandler).Name("baba")
+	}
+
+	ass.Panics(t, action, "failed to panic on duplicate route name")
+}
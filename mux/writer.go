@@ -0,0 +1,107 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package mux
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/go-lean/fun/resp"
+)
+
+// headResponseWriter discards the body written by a GET handler serving a HEAD request. The status
+// code is held back until the handler returns, so the Content-Length of the discarded body can
+// still be reported.
+type headResponseWriter struct {
+	http.ResponseWriter
+	code   int
+	length int
+}
+
+func (w *headResponseWriter) WriteHeader(code int) {
+
+	if w.code != 0 {
+		return
+	}
+
+	w.code = code
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+
+	w.WriteHeader(http.StatusOK)
+	w.length += len(b)
+
+	return len(b), nil
+}
+
+func (w *headResponseWriter) finish() {
+
+	w.WriteHeader(http.StatusOK)
+
+	header := w.Header()
+	if w.length > 0 && header.Get("Content-Length") == "" {
+		header.Set("Content-Length", strconv.Itoa(w.length))
+	}
+
+	w.ResponseWriter.WriteHeader(w.code)
+}
+
+// bufferResponseWriter holds a response written by a plain http.Handler, so it can be passed
+// through middle steps as a resp.Result.
+type bufferResponseWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *bufferResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferResponseWriter) WriteHeader(code int) {
+
+	if w.code != 0 {
+		return
+	}
+
+	w.code = code
+}
+
+func (w *bufferResponseWriter) Write(b []byte) (int, error) {
+
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// result returns the buffered response. Bodies without a Content-Type get a sniffed one, as
+// net/http would have set.
+func (w *bufferResponseWriter) result() resp.Result {
+
+	w.WriteHeader(http.StatusOK)
+
+	contentType := w.header.Get("Content-Type")
+	w.header.Del("Content-Type")
+	w.header.Del("Content-Length")
+
+	var payload any
+	if w.body.Len() > 0 {
+		payload = w.body.Bytes()
+		if contentType == "" {
+			contentType = http.DetectContentType(w.body.Bytes())
+		}
+	}
+
+	return resp.Result{
+		Payload: payload,
+		Code:    w.code,
+		Type:    contentType,
+		Header:  w.header,
+	}
+}
@@ -0,0 +1,345 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type (
+	// Encoder serializes payload to w.
+	Encoder func(w io.Writer, payload any) error
+
+	// Registry maps media types to the encoders used to serialize result payloads.
+	Registry struct {
+		mu       sync.RWMutex
+		encoders map[string]Encoder
+		types    []string
+	}
+)
+
+const (
+	TypeJSON   = "application/json"
+	TypeXML    = "application/xml"
+	TypeText   = "text/plain"
+	TypeForm   = "application/x-www-form-urlencoded"
+	TypeCSV    = "text/csv"
+	TypeBinary = "application/octet-stream"
+)
+
+var (
+	ErrUnsupportedType = errors.New("resp: no encoder registered for content type")
+
+	DefaultRegistry = NewRegistry().
+			Register(TypeJSON, EncodeJSON).
+			Register(TypeXML, EncodeXML).
+			Register(TypeText, EncodeText).
+			Register(TypeForm, EncodeForm).
+			Register(TypeCSV, EncodeCSV).
+			Register(TypeBinary, EncodeBinary)
+)
+
+func NewRegistry() *Registry {
+
+	return &Registry{
+		encoders: make(map[string]Encoder),
+	}
+}
+
+// RegisterEncoder registers encoder for mediaType in the DefaultRegistry.
+func RegisterEncoder(mediaType string, encoder Encoder) {
+	DefaultRegistry.Register(mediaType, encoder)
+}
+
+// Register adds or replaces the encoder for mediaType. Parameters such as charset are ignored.
+func (reg *Registry) Register(mediaType string, encoder Encoder) *Registry {
+
+	mediaType = mediaTypeOf(mediaType)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if _, ok := reg.encoders[mediaType]; !ok {
+		reg.types = append(reg.types, mediaType)
+	}
+
+	reg.encoders[mediaType] = encoder
+	return reg
+}
+
+// Lookup returns the encoder for contentType. Structured syntax suffixes such as "+json" and
+// "+xml" fall back to the encoder of their base type.
+func (reg *Registry) Lookup(contentType string) (Encoder, bool) {
+
+	mediaType := mediaTypeOf(contentType)
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	if encoder, ok := reg.encoders[mediaType]; ok {
+		return encoder, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		encoder, ok := reg.encoders[TypeJSON]
+		return encoder, ok
+	case strings.HasSuffix(mediaType, "+xml"):
+		encoder, ok := reg.encoders[TypeXML]
+		return encoder, ok
+	}
+
+	return nil, false
+}
+
+// Types returns the registered media types in registration order.
+func (reg *Registry) Types() []string {
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	types := make([]string, len(reg.types))
+	copy(types, reg.types)
+
+	return types
+}
+
+// typesFor returns the registered media types whose encoders can serialize payload, in
+// registration order.
+func (reg *Registry) typesFor(payload any) []string {
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	var types []string
+	for _, mediaType := range reg.types {
+		if canEncode(reg.encoders[mediaType], payload) {
+			types = append(types, mediaType)
+		}
+	}
+
+	return types
+}
+
+func (reg *Registry) Clone() *Registry {
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	clone := NewRegistry()
+	for _, mediaType := range reg.types {
+		clone.types = append(clone.types, mediaType)
+		clone.encoders[mediaType] = reg.encoders[mediaType]
+	}
+
+	return clone
+}
+
+func EncodeJSON(w io.Writer, payload any) error {
+
+	if err, ok := payload.(error); ok {
+		if _, isMarshaler := payload.(json.Marshaler); !isMarshaler {
+			payload = map[string]string{"error": err.Error()}
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+func EncodeXML(w io.Writer, payload any) error {
+
+	body, err := xml.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+	return err
+}
+
+func EncodeText(w io.Writer, payload any) error {
+
+	var err error
+	switch p := payload.(type) {
+	case string:
+		_, err = io.WriteString(w, p)
+	case error:
+		_, err = io.WriteString(w, p.Error())
+	case fmt.Stringer:
+		_, err = io.WriteString(w, p.String())
+	default:
+		_, err = fmt.Fprint(w, p)
+	}
+
+	return err
+}
+
+func EncodeForm(w io.Writer, payload any) error {
+
+	var values url.Values
+	switch p := payload.(type) {
+	case url.Values:
+		values = p
+	case map[string][]string:
+		values = p
+	case map[string]string:
+		values = make(url.Values, len(p))
+		for key, value := range p {
+			values.Set(key, value)
+		}
+	default:
+		return unsupportedPayload(payload, TypeForm)
+	}
+
+	_, err := io.WriteString(w, values.Encode())
+	return err
+}
+
+func EncodeCSV(w io.Writer, payload any) error {
+
+	var records [][]string
+	switch p := payload.(type) {
+	case [][]string:
+		records = p
+	case []string:
+		records = [][]string{p}
+	default:
+		return unsupportedPayload(payload, TypeCSV)
+	}
+
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+func EncodeBinary(w io.Writer, payload any) error {
+
+	switch p := payload.(type) {
+	case []byte:
+		_, err := w.Write(p)
+		return err
+	case string:
+		_, err := io.WriteString(w, p)
+		return err
+	}
+
+	return unsupportedPayload(payload, TypeBinary)
+}
+
+// payloadChecks reports which payloads the built-in encoders can serialize, keyed by encoder.
+var payloadChecks = map[uintptr]func(payload any) bool{
+	reflect.ValueOf(EncodeXML).Pointer():    encodesXML,
+	reflect.ValueOf(EncodeText).Pointer():   encodesText,
+	reflect.ValueOf(EncodeForm).Pointer():   encodesForm,
+	reflect.ValueOf(EncodeCSV).Pointer():    encodesCSV,
+	reflect.ValueOf(EncodeBinary).Pointer(): encodesBinary,
+}
+
+// canEncode reports whether encoder can serialize payload. Encoders other than the built-in ones
+// are trusted with any payload.
+func canEncode(encoder Encoder, payload any) bool {
+
+	check, ok := payloadChecks[reflect.ValueOf(encoder).Pointer()]
+	return !ok || check(payload)
+}
+
+func encodesXML(payload any) bool {
+
+	if _, ok := payload.(xml.Marshaler); ok {
+		return true
+	}
+
+	value := reflect.ValueOf(payload)
+	for value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.Invalid:
+		return false
+	}
+
+	return true
+}
+
+func encodesText(payload any) bool {
+
+	switch payload.(type) {
+	case string, error, fmt.Stringer:
+		return true
+	}
+
+	switch reflect.ValueOf(payload).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func encodesForm(payload any) bool {
+
+	switch payload.(type) {
+	case url.Values, map[string][]string, map[string]string:
+		return true
+	}
+
+	return false
+}
+
+func encodesCSV(payload any) bool {
+
+	switch payload.(type) {
+	case [][]string, []string:
+		return true
+	}
+
+	return false
+}
+
+func encodesBinary(payload any) bool {
+
+	switch payload.(type) {
+	case []byte, string:
+		return true
+	}
+
+	return false
+}
+
+func unsupportedPayload(payload any, mediaType string) error {
+	return fmt.Errorf("resp: cannot encode %T as %s", payload, mediaType)
+}
+
+func mediaTypeOf(contentType string) string {
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		return mediaType
+	}
+
+	mediaType, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,122 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/resp"
+)
+
+type person struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestRegistry_DefaultEncoders(t *testing.T) {
+
+	tc := []struct {
+		name        string
+		payload     any
+		contentType string
+		body        string
+	}{
+		{"json", person{"baba"}, resp.TypeJSON, `{"name":"baba"}`},
+		{"json with params", person{"baba"}, "application/json; charset=utf-8", `{"name":"baba"}`},
+		{"json suffix", person{"baba"}, "application/vnd.baba+json", `{"name":"baba"}`},
+		{"json error", errors.New("baba"), resp.TypeJSON, `{"error":"baba"}`},
+		{"xml", person{"baba"}, resp.TypeXML, `<person><name>baba</name></person>`},
+		{"xml suffix", person{"baba"}, "application/atom+xml", `<person><name>baba</name></person>`},
+		{"text", "baba", resp.TypeText, "baba"},
+		{"text number", 42, resp.TypeText, "42"},
+		{"form", url.Values{"name": {"baba"}}, resp.TypeForm, "name=baba"},
+		{"form map", map[string]string{"name": "baba"}, resp.TypeForm, "name=baba"},
+		{"csv", [][]string{{"baba", "is"}, {"you", "win"}}, resp.TypeCSV, "baba,is\nyou,win\n"},
+		{"binary", "baba", resp.TypeBinary, "baba"},
+		{"raw bytes for any type", []byte("baba"), "image/png", "baba"},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			err := resp.Write(w, r, resp.New(http.StatusOK, c.payload, c.contentType))
+
+			ass.Equal(t, nil, err, "unexpected error")
+			ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+			ass.Equal(t, c.contentType, w.Header().Get("Content-Type"), "wrong content type")
+			ass.Equal(t, c.body, w.Body.String(), "wrong body")
+		})
+	}
+}
+
+func TestRegistry_UnsupportedPayload_InternalServerError(t *testing.T) {
+
+	for _, contentType := range []string{resp.TypeForm, resp.TypeCSV, resp.TypeBinary} {
+		t.Run(contentType, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			err := resp.Write(w, r, resp.New(http.StatusOK, person{"baba"}, contentType))
+
+			ass.True(t, err != nil, "expected an encoding error")
+			ass.Equal(t, http.StatusInternalServerError, w.Code, "wrong status code")
+		})
+	}
+}
+
+func TestRegistry_UnknownType_ErrorResult(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := resp.Write(w, r, resp.New(http.StatusOK, "baba", "baba/plain"))
+
+	ass.True(t, errors.Is(err, resp.ErrUnsupportedType), "wrong error")
+	ass.Equal(t, http.StatusInternalServerError, w.Code, "wrong status code")
+	ass.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"), "wrong content type")
+	ass.True(t, strings.Contains(w.Body.String(), `"baba/plain"`), "body does not name the type")
+}
+
+func TestRegistry_CustomType(t *testing.T) {
+	registry := resp.DefaultRegistry.Clone().
+		Register("baba/plain", func(w io.Writer, payload any) error {
+			_, err := io.WriteString(w, "baba is "+payload.(string))
+			return err
+		})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := registry.Write(w, r, resp.New(http.StatusOK, "you", "baba/plain"))
+
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, "baba is you", w.Body.String(), "wrong body")
+
+	_, registered := resp.DefaultRegistry.Lookup("baba/plain")
+	ass.False(t, registered, "clone leaked into the default registry")
+}
+
+func TestRegistry_Types(t *testing.T) {
+	registry := resp.NewRegistry().
+		Register(resp.TypeJSON, resp.EncodeJSON).
+		Register("Text/CSV; charset=utf-8", resp.EncodeCSV).
+		Register(resp.TypeJSON, resp.EncodeJSON)
+
+	types := registry.Types()
+
+	ass.Equal(t, 2, len(types), "wrong types count")
+	ass.Equal(t, resp.TypeJSON, types[0], "wrong first type")
+	ass.Equal(t, resp.TypeCSV, types[1], "wrong second type")
+}
@@ -0,0 +1,156 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type mediaRange struct {
+	mainType string
+	subType  string
+	q        float64
+}
+
+// Negotiate picks the representation of res using the DefaultRegistry.
+func Negotiate(r *http.Request, res Result) Result {
+	return DefaultRegistry.Negotiate(r, res)
+}
+
+// Negotiate sets res.Type to the registered media type that best matches the Accept header of r,
+// honouring q-values and wildcards. Only types whose encoder can serialize the payload are offered,
+// and the default type of the payload wins ties. Results that already have a type, carry no payload
+// or carry raw bytes or readers are returned unchanged and problems are always typed as
+// problem+json. When no offered type is acceptable a 406 problem listing them is returned instead.
+func (reg *Registry) Negotiate(r *http.Request, res Result) Result {
+
+	if res.Type != "" || res.Payload == nil || !bodyAllowed(res.Code) {
+		return res
+	}
+
+	switch res.Payload.(type) {
+	case []byte, io.Reader:
+		return res
+	case *Problem:
+		res.Type = TypeProblemJSON
+		return res
+	}
+
+	defaultType := defaultTypeFor(res.Payload)
+
+	accept := ""
+	if r != nil {
+		accept = strings.Join(r.Header.Values("Accept"), ",")
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		res.Type = defaultType
+		return res
+	}
+
+	res.Header = withVary(res.Header, "Accept")
+
+	ranges := parseAccept(accept)
+	supported := reg.typesFor(res.Payload)
+	offers := append([]string{mediaTypeOf(defaultType)}, supported...)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q := qualityOf(offer, ranges)
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	if best == "" {
+		problem := NewProblem(http.StatusNotAcceptable, "none of the accepted types is supported")
+		return problem.With("supported", supported).Result(WithHeaders(res.Header))
+	}
+
+	if best == TypeText {
+		best = typeTextUTF8
+	}
+
+	res.Type = best
+	return res
+}
+
+func withVary(header http.Header, value string) http.Header {
+
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	header.Add("Vary", value)
+	return header
+}
+
+func qualityOf(offer string, ranges []mediaRange) float64 {
+
+	mainType, subType, _ := strings.Cut(offer, "/")
+
+	specificity, q := -1, 0.0
+	for _, rng := range ranges {
+		current := -1
+		switch {
+		case rng.mainType == mainType && rng.subType == subType:
+			current = 2
+		case rng.mainType == mainType && rng.subType == "*":
+			current = 1
+		case rng.mainType == "*" && rng.subType == "*":
+			current = 0
+		}
+
+		if current > specificity {
+			specificity, q = current, rng.q
+		}
+	}
+
+	return q
+}
+
+func parseAccept(accept string) []mediaRange {
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		mainType, subType, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{
+			mainType: mainType,
+			subType:  subType,
+			q:        q,
+		})
+	}
+
+	return ranges
+}
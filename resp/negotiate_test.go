@@ -0,0 +1,118 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/resp"
+)
+
+func TestNegotiate_Accept(t *testing.T) {
+
+	tc := []struct {
+		name        string
+		accept      string
+		payload     any
+		contentType string
+	}{
+		{"no accept - default struct", "", person{"baba"}, resp.TypeJSON},
+		{"no accept - default string", "", "baba", "text/plain; charset=utf-8"},
+		{"exact", "application/xml", person{"baba"}, resp.TypeXML},
+		{"any - default wins", "*/*", person{"baba"}, resp.TypeJSON},
+		{"q-values", "application/json;q=0.9, application/xml", person{"baba"}, resp.TypeXML},
+		{"equal q-values - default wins", "application/xml, application/json", person{"baba"}, resp.TypeJSON},
+		{"subtype wildcard", "text/*", [][]string{{"baba"}}, resp.TypeCSV},
+		{"specific range overrides wildcard", "*/*, application/json;q=0", person{"baba"}, resp.TypeXML},
+		{"invalid q ignored", "application/xml;q=2, application/json;q=0.1", person{"baba"}, resp.TypeJSON},
+		{"browser - map skips xml", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", map[string]any{"name": "baba"}, resp.TypeJSON},
+		{"browser - struct", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", person{"baba"}, resp.TypeXML},
+		{"text skipped for struct", "text/plain, application/json;q=0.5", person{"baba"}, resp.TypeJSON},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if c.accept != "" {
+				r.Header.Set("Accept", c.accept)
+			}
+
+			res := resp.Negotiate(r, resp.New(http.StatusOK, c.payload, ""))
+
+			ass.Equal(t, http.StatusOK, res.Code, "wrong status code")
+			ass.Equal(t, c.contentType, res.Type, "wrong content type")
+		})
+	}
+}
+
+func TestNegotiate_TypeSet_Unchanged(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+
+	res := resp.Negotiate(r, resp.New(http.StatusOK, person{"baba"}, resp.TypeJSON))
+
+	ass.Equal(t, resp.TypeJSON, res.Type, "wrong content type")
+}
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "image/png")
+
+	res := resp.Negotiate(r, resp.New(http.StatusOK, person{"baba"}, ""))
+
+	ass.Equal(t, http.StatusNotAcceptable, res.Code, "wrong status code")
+	problem := res.Payload.(*resp.Problem)
+	supported := problem.Extensions["supported"].([]string)
+
+	ass.Equal(t, resp.TypeProblemJSON, res.Type, "wrong content type")
+	ass.Equal(t, resp.TypeJSON, supported[0], "supported types not listed")
+	ass.Equal(t, "Accept", res.Header.Get("Vary"), "wrong vary header")
+}
+
+func TestNegotiate_NotAcceptable_ForPayload(t *testing.T) {
+
+	for _, accept := range []string{"text/plain", "text/csv", "application/x-www-form-urlencoded"} {
+		t.Run(accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", accept)
+
+			res := resp.Negotiate(r, resp.New(http.StatusOK, person{"baba"}, ""))
+
+			ass.Equal(t, http.StatusNotAcceptable, res.Code, "wrong status code")
+			supported := res.Payload.(*resp.Problem).Extensions["supported"].([]string)
+			ass.Equal(t, "application/json,application/xml", strings.Join(supported, ","), "wrong supported types")
+		})
+	}
+}
+
+func TestWrite_Negotiated_Browser(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	_ = resp.Write(w, r, resp.New(http.StatusOK, map[string]any{"name": "baba"}, ""))
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, resp.TypeJSON, w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, `{"name":"baba"}`, w.Body.String(), "wrong body")
+}
+
+func TestWrite_Negotiated(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json;q=0.9, application/xml")
+
+	_ = resp.Write(w, r, resp.New(http.StatusOK, person{"baba"}, ""))
+
+	ass.Equal(t, resp.TypeXML, w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, "<person><name>baba</name></person>", w.Body.String(), "wrong body")
+}
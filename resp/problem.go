@@ -0,0 +1,143 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+type (
+	// Problem is an RFC 9457 problem details object. Extensions are serialized as additional
+	// top-level members and can not override the standard ones.
+	Problem struct {
+		Type       string
+		Title      string
+		Status     int
+		Detail     string
+		Instance   string
+		Extensions map[string]any
+	}
+
+	FieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+)
+
+const TypeProblemJSON = "application/problem+json"
+
+func NewProblem(status int, detail string) *Problem {
+
+	return &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// ProblemFrom returns a copy of the Problem wrapped in err or a new one with the given status.
+// The error message becomes the detail only for client errors, so server internals do not leak.
+func ProblemFrom(status int, err error) *Problem {
+
+	var problem *Problem
+	if errors.As(err, &problem) {
+		clone := *problem
+		clone.Extensions = maps.Clone(problem.Extensions)
+
+		return &clone
+	}
+
+	if status >= http.StatusInternalServerError {
+		return NewProblem(status, "")
+	}
+
+	return NewProblem(status, err.Error())
+}
+
+func BadRequest(detail string) Result {
+	return NewProblem(http.StatusBadRequest, detail).Result()
+}
+
+func NotFound(detail string) Result {
+	return NewProblem(http.StatusNotFound, detail).Result()
+}
+
+func Conflict(detail string) Result {
+	return NewProblem(http.StatusConflict, detail).Result()
+}
+
+// Unprocessable returns a 422 problem listing fieldErrors under the "errors" member.
+func Unprocessable(detail string, fieldErrors ...FieldError) Result {
+
+	problem := NewProblem(http.StatusUnprocessableEntity, detail)
+	if len(fieldErrors) > 0 {
+		problem.With("errors", fieldErrors)
+	}
+
+	return problem.Result()
+}
+
+func (p *Problem) With(key string, value any) *Problem {
+
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+
+	p.Extensions[key] = value
+	return p
+}
+
+func (p *Problem) Result(opts ...Opts) Result {
+
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	return New(status, p, TypeProblemJSON, opts...)
+}
+
+func (p *Problem) Error() string {
+
+	if p.Detail == "" {
+		return p.Title
+	}
+
+	return p.Title + ": " + p.Detail
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	setMember(members, "type", p.Type)
+	setMember(members, "title", p.Title)
+	setMember(members, "detail", p.Detail)
+	setMember(members, "instance", p.Instance)
+
+	delete(members, "status")
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+
+	return json.Marshal(members)
+}
+
+func setMember(members map[string]any, key, value string) {
+
+	delete(members, key)
+	if value != "" {
+		members[key] = value
+	}
+}
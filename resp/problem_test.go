@@ -0,0 +1,97 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/resp"
+)
+
+func TestProblem_Helpers(t *testing.T) {
+
+	tc := []struct {
+		name   string
+		result resp.Result
+		status int
+	}{
+		{"bad request", resp.BadRequest("baba"), http.StatusBadRequest},
+		{"not found", resp.NotFound("baba"), http.StatusNotFound},
+		{"conflict", resp.Conflict("baba"), http.StatusConflict},
+		{"unprocessable", resp.Unprocessable("baba"), http.StatusUnprocessableEntity},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			problem := c.result.Payload.(*resp.Problem)
+
+			ass.Equal(t, c.status, c.result.Code, "wrong status code")
+			ass.Equal(t, resp.TypeProblemJSON, c.result.Type, "wrong content type")
+			ass.Equal(t, c.status, problem.Status, "wrong problem status")
+			ass.Equal(t, http.StatusText(c.status), problem.Title, "wrong title")
+			ass.Equal(t, "baba", problem.Detail, "wrong detail")
+		})
+	}
+}
+
+func TestProblem_Write(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+
+	res := resp.Unprocessable("invalid input", resp.FieldError{Field: "name", Message: "required"})
+	res.Payload.(*resp.Problem).Instance = "/baba"
+
+	_ = resp.Write(w, r, res)
+
+	expected := `{"detail":"invalid input","errors":[{"field":"name","message":"required"}],` +
+		`"instance":"/baba","status":422,"title":"Unprocessable Entity"}`
+
+	ass.Equal(t, http.StatusUnprocessableEntity, w.Code, "wrong status code")
+	ass.Equal(t, resp.TypeProblemJSON, w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, expected, w.Body.String(), "wrong body")
+}
+
+func TestProblem_Extensions_DoNotOverrideMembers(t *testing.T) {
+	problem := resp.NewProblem(http.StatusNotFound, "baba").
+		With("title", "flag").
+		With("status", 200).
+		With("balance", 30)
+
+	body, err := problem.MarshalJSON()
+
+	ass.Equal(t, nil, err, "unexpected error")
+	ass.Equal(t, `{"balance":30,"detail":"baba","status":404,"title":"Not Found"}`, string(body), "wrong body")
+}
+
+func TestProblemFrom(t *testing.T) {
+	original := resp.NewProblem(http.StatusConflict, "baba").With("id", 1)
+
+	problem := resp.ProblemFrom(http.StatusInternalServerError, fmt.Errorf("wrapped: %w", original))
+	problem.With("id", 2)
+
+	ass.Equal(t, http.StatusConflict, problem.Status, "wrong status")
+	ass.Equal(t, 1, original.Extensions["id"].(int), "original problem was modified")
+
+	problem = resp.ProblemFrom(http.StatusBadRequest, errors.New("baba"))
+	ass.Equal(t, "baba", problem.Detail, "wrong client error detail")
+
+	problem = resp.ProblemFrom(http.StatusInternalServerError, errors.New("baba"))
+	ass.EmptyString(t, problem.Detail, "server error detail leaked")
+}
+
+func TestProblem_Error(t *testing.T) {
+	err := error(resp.NewProblem(http.StatusNotFound, "baba"))
+
+	ass.Equal(t, "Not Found: baba", err.Error(), "wrong message")
+}
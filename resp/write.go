@@ -0,0 +1,152 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const typeTextUTF8 = TypeText + "; charset=utf-8"
+
+// Write writes res to w using the DefaultRegistry.
+func Write(w http.ResponseWriter, r *http.Request, res Result) error {
+	return DefaultRegistry.Write(w, r, res)
+}
+
+// Write applies the headers, content type and status code of res to w and serializes its payload
+// with the encoder registered for res.Type. Results without a type are negotiated against the
+// Accept header of r, defaulting to text for strings and errors, binary for byte slices and readers
+// and JSON for anything else. Byte slices and readers are always written verbatim. Nil payloads,
+// 1xx, 204 and 304 responses and responses to HEAD requests get no body, though closable payloads
+// are still closed. Types without an encoder result in a 500 naming the type.
+func (reg *Registry) Write(w http.ResponseWriter, r *http.Request, res Result) error {
+
+	res = reg.Negotiate(r, res)
+
+	header := w.Header()
+	for key, values := range res.Header {
+		header.Del(key)
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	code := res.Code
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	if res.Payload == nil || !bodyAllowed(code) {
+		if closer, ok := res.Payload.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		if res.Type != "" && bodyAllowed(code) {
+			header.Set("Content-Type", res.Type)
+		}
+
+		w.WriteHeader(code)
+		return nil
+	}
+
+	isHead := r != nil && r.Method == http.MethodHead
+
+	contentType := res.Type
+	if contentType == "" {
+		contentType = defaultTypeFor(res.Payload)
+	}
+
+	if reader, ok := res.Payload.(io.Reader); ok {
+		header.Set("Content-Type", contentType)
+		w.WriteHeader(code)
+
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		if isHead {
+			return nil
+		}
+
+		_, err := io.Copy(w, reader)
+		return err
+	}
+
+	var body []byte
+	if raw, ok := res.Payload.([]byte); ok {
+		body = raw
+	} else {
+		encoder, ok := reg.Lookup(contentType)
+		if !ok {
+			err := fmt.Errorf("%w %q", ErrUnsupportedType, contentType)
+			writeError(w, err.Error())
+
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		if err := encoder(buf, res.Payload); err != nil {
+			writeError(w, http.StatusText(http.StatusInternalServerError))
+			return err
+		}
+
+		body = buf.Bytes()
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+
+	if isHead {
+		return nil
+	}
+
+	_, err := w.Write(body)
+	return err
+}
+
+func writeError(w http.ResponseWriter, message string) {
+
+	header := w.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Type", typeTextUTF8)
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	_, _ = io.WriteString(w, message)
+}
+
+func defaultTypeFor(payload any) string {
+
+	switch payload.(type) {
+	case string, error:
+		return typeTextUTF8
+	case []byte, io.Reader:
+		return TypeBinary
+	case *Problem:
+		return TypeProblemJSON
+	}
+
+	return TypeJSON
+}
+
+func bodyAllowed(code int) bool {
+
+	switch {
+	case code >= 100 && code <= 199:
+		return false
+	case code == http.StatusNoContent, code == http.StatusNotModified:
+		return false
+	}
+
+	return true
+}
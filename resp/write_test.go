@@ -0,0 +1,164 @@
+/*
+	Copyright (c) 2023 go-lean
+
+	This software is licensed under the MIT License.
+	The full license agreement can be found in the LICENSE file.
+*/
+
+package resp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-lean/fun/ass"
+	"github.com/go-lean/fun/resp"
+)
+
+type closingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestWrite_Payloads(t *testing.T) {
+
+	tc := []struct {
+		name        string
+		payload     any
+		contentType string
+		body        string
+	}{
+		{"string", "baba", "text/plain; charset=utf-8", "baba"},
+		{"bytes", []byte("baba"), "application/octet-stream", "baba"},
+		{"reader", strings.NewReader("baba"), "application/octet-stream", "baba"},
+		{"error", errors.New("baba"), "text/plain; charset=utf-8", "baba"},
+		{"struct", struct {
+			Name string `json:"name"`
+		}{"baba"}, "application/json", `{"name":"baba"}`},
+		{"map", map[string]int{"baba": 1}, "application/json", `{"baba":1}`},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			err := resp.Write(w, r, resp.New(http.StatusOK, c.payload, ""))
+
+			ass.Equal(t, nil, err, "unexpected error")
+			ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+			ass.Equal(t, c.contentType, w.Header().Get("Content-Type"), "wrong content type")
+			ass.Equal(t, c.body, w.Body.String(), "wrong body")
+		})
+	}
+}
+
+func TestWrite_HeadersAndType(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Baba", "is-you")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	res := resp.New(http.StatusCreated, []byte("baba"), "baba/plain", resp.WithHeaders(header))
+	_ = resp.Write(w, r, res)
+
+	ass.Equal(t, http.StatusCreated, w.Code, "wrong status code")
+	ass.Equal(t, "baba/plain", w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, "is-you", w.Header().Get("X-Baba"), "wrong header")
+	ass.Equal(t, "4", w.Header().Get("Content-Length"), "wrong content length")
+}
+
+func TestWrite_ZeroCode_DefaultsToOK(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_ = resp.Write(w, r, resp.Result{Payload: "baba"})
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+}
+
+func TestWrite_NilPayload_NoBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_ = resp.Write(w, r, resp.New(http.StatusAccepted, nil, ""))
+
+	ass.Equal(t, http.StatusAccepted, w.Code, "wrong status code")
+	ass.EmptyString(t, w.Body.String(), "unexpected body")
+	ass.EmptyString(t, w.Header().Get("Content-Type"), "unexpected content type")
+}
+
+func TestWrite_BodylessCodes(t *testing.T) {
+
+	for _, code := range []int{http.StatusNoContent, http.StatusNotModified} {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			_ = resp.Write(w, r, resp.New(code, "baba", "text/plain"))
+
+			ass.Equal(t, code, w.Code, "wrong status code")
+			ass.EmptyString(t, w.Body.String(), "unexpected body")
+			ass.EmptyString(t, w.Header().Get("Content-Type"), "unexpected content type")
+		})
+
+		t.Run(http.StatusText(code)+" reader", func(t *testing.T) {
+			reader := &closingReader{Reader: strings.NewReader("baba")}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			_ = resp.Write(w, r, resp.New(code, reader, "text/csv"))
+
+			ass.Equal(t, code, w.Code, "wrong status code")
+			ass.EmptyString(t, w.Body.String(), "unexpected body")
+			ass.True(t, reader.closed, "reader was not closed")
+		})
+	}
+}
+
+func TestWrite_Head_KeepsHeadersWithoutBody(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodHead, "/", nil)
+
+	_ = resp.Write(w, r, resp.New(http.StatusOK, "baba", ""))
+
+	ass.Equal(t, http.StatusOK, w.Code, "wrong status code")
+	ass.Equal(t, "4", w.Header().Get("Content-Length"), "wrong content length")
+	ass.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"), "wrong content type")
+	ass.EmptyString(t, w.Body.String(), "unexpected body")
+}
+
+func TestWrite_Reader_Closed(t *testing.T) {
+	reader := &closingReader{Reader: strings.NewReader("baba")}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_ = resp.Write(w, r, resp.New(http.StatusOK, reader, "text/csv"))
+
+	ass.Equal(t, "text/csv", w.Header().Get("Content-Type"), "wrong content type")
+	ass.Equal(t, "baba", w.Body.String(), "wrong body")
+	ass.True(t, reader.closed, "reader was not closed")
+}
+
+func TestWrite_UnencodablePayload_InternalServerError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := resp.Write(w, r, resp.New(http.StatusOK, make(chan int), ""))
+
+	ass.True(t, err != nil, "expected an encoding error")
+	ass.Equal(t, http.StatusInternalServerError, w.Code, "wrong status code")
+	ass.Equal(t, http.StatusText(http.StatusInternalServerError), w.Body.String(), "wrong body")
+}